@@ -15,6 +15,7 @@ import (
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/opencontainers/runtime-tools/generate/seccomp"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
 
@@ -41,6 +42,7 @@ var generateFlags = []cli.Flag{
 	cli.StringSliceFlag{Name: "linux-blkio-write-bps-device", Usage: "Limit write rate (bytes per second) to a device"},
 	cli.StringSliceFlag{Name: "linux-blkio-write-iops-device", Usage: "Limit write rate (IO per second) to a device"},
 	cli.StringFlag{Name: "linux-cgroups-path", Usage: "specify the path to the cgroups"},
+	cli.Uint64Flag{Name: "linux-cpu-burst", Usage: "the CPU time a cgroup may use beyond its quota, accumulated while it is idle (in usecs)"},
 	cli.Uint64Flag{Name: "linux-cpu-period", Usage: "the CPU period to be used for hardcapping (in usecs)"},
 	cli.Uint64Flag{Name: "linux-cpu-quota", Usage: "the allowed CPU time in a given period (in usecs)"},
 	cli.StringFlag{Name: "linux-cpus", Usage: "CPUs to use within the cpuset (default is to use any CPU available)"},
@@ -57,12 +59,12 @@ var generateFlags = []cli.Flag{
 	cli.StringFlag{Name: "linux-intelRdt-closid", Usage: "RDT Class of Service, i.e. group under the resctrl pseudo-filesystem which to associate the container with"},
 	cli.StringFlag{Name: "linux-intelRdt-l3CacheSchema", Usage: "specifies the schema for L3 cache id and capacity bitmask"},
 	cli.StringSliceFlag{Name: "linux-masked-paths", Usage: "specifies paths can not be read inside container"},
-	cli.Uint64Flag{Name: "linux-mem-kernel-limit", Usage: "kernel memory limit (in bytes)"},
-	cli.Uint64Flag{Name: "linux-mem-kernel-tcp", Usage: "kernel memory limit for tcp (in bytes)"},
-	cli.Uint64Flag{Name: "linux-mem-limit", Usage: "memory limit (in bytes)"},
-	cli.Uint64Flag{Name: "linux-mem-reservation", Usage: "memory reservation or soft limit (in bytes)"},
+	cli.StringFlag{Name: "linux-mem-kernel-limit", Usage: "kernel memory limit (in bytes, or with a k, m or g suffix)"},
+	cli.StringFlag{Name: "linux-mem-kernel-tcp", Usage: "kernel memory limit for tcp (in bytes, or with a k, m or g suffix)"},
+	cli.StringFlag{Name: "linux-mem-limit", Usage: "memory limit (in bytes, or with a k, m or g suffix)"},
+	cli.StringFlag{Name: "linux-mem-reservation", Usage: "memory reservation or soft limit (in bytes, or with a k, m or g suffix)"},
 	cli.StringFlag{Name: "linux-mems", Usage: "list of memory nodes in the cpuset (default is to use any available memory node)"},
-	cli.Uint64Flag{Name: "linux-mem-swap", Usage: "total memory limit (memory + swap) (in bytes)"},
+	cli.StringFlag{Name: "linux-mem-swap", Usage: "total memory limit (memory + swap) (in bytes, or with a k, m or g suffix)"},
 	cli.Uint64Flag{Name: "linux-mem-swappiness", Usage: "how aggressive the kernel will swap memory pages (Range from 0 to 100)"},
 	cli.StringFlag{Name: "linux-mount-label", Usage: "selinux mount context label"},
 	cli.StringSliceFlag{Name: "linux-namespace-add", Usage: "adds a namespace to the set of namespaces to create or join of the form 'ns[:path]'"},
@@ -124,6 +126,7 @@ var generateFlags = []cli.Flag{
 	cli.StringFlag{Name: "process-username", Usage: "username for the process"},
 	cli.StringFlag{Name: "rootfs-path", Value: "rootfs", Usage: "path to the root filesystem"},
 	cli.BoolFlag{Name: "rootfs-readonly", Usage: "make the container's rootfs readonly"},
+	cli.BoolFlag{Name: "show-diff", Usage: "output only the fields which differ from the default configuration"},
 	cli.StringSliceFlag{Name: "solaris-anet", Usage: "set up networking for Solaris application containers"},
 	cli.StringFlag{Name: "solaris-capped-cpu-ncpus", Usage: "Specifies the percentage of CPU usage"},
 	cli.StringFlag{Name: "solaris-capped-memory-physical", Usage: "Specifies the physical caps on the memory"},
@@ -131,6 +134,7 @@ var generateFlags = []cli.Flag{
 	cli.StringFlag{Name: "solaris-limitpriv", Usage: "privilege limit"},
 	cli.StringFlag{Name: "solaris-max-shm-memory", Usage: "Specifies the maximum amount of shared memory"},
 	cli.StringFlag{Name: "solaris-milestone", Usage: "Specifies the SMF FMRI"},
+	cli.BoolFlag{Name: "strict", Usage: "fail instead of warning when the generated configuration is invalid"},
 	cli.StringFlag{Name: "template", Usage: "base template to use for creating the configuration"},
 	cli.StringSliceFlag{Name: "vm-hypervisor-parameters", Usage: "specifies an array of parameters to pass to the hypervisor"},
 	cli.StringFlag{Name: "vm-hypervisor-path", Usage: "specifies the path to the hypervisor binary that manages the container virtual machine"},
@@ -180,6 +184,27 @@ var generateCommand = cli.Command{
 			return err
 		}
 
+		for _, w := range specgen.Warnings() {
+			logrus.Warn(w)
+		}
+		if errs := specgen.Validate(); len(errs) > 0 {
+			strict := context.Bool("strict")
+			for _, e := range errs {
+				if strict {
+					logrus.Error(e)
+				} else {
+					logrus.Warn(e)
+				}
+			}
+			if strict {
+				return fmt.Errorf("generated configuration has %d validation errors", len(errs))
+			}
+		}
+
+		if context.Bool("show-diff") {
+			return showDiff(&specgen, context)
+		}
+
 		var exportOpts generate.ExportOptions
 		exportOpts.Seccomp = context.Bool("linux-seccomp-only")
 
@@ -195,6 +220,25 @@ var generateCommand = cli.Command{
 	},
 }
 
+// showDiff writes the fields of g which differ from the default
+// configuration, as JSON, to the output file or stdout.
+func showDiff(g *generate.Generator, context *cli.Context) error {
+	diff, err := g.DiffFromDefault()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(diff, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if context.IsSet("output") {
+		return os.WriteFile(context.String("output"), data, 0o644)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
 func setupSpec(g *generate.Generator, context *cli.Context) error {
 	if context.GlobalBool("host-specific") {
 		g.HostSpecific = true
@@ -268,7 +312,9 @@ func setupSpec(g *generate.Generator, context *cli.Context) error {
 	}
 
 	if context.IsSet("args") {
-		g.SetProcessArgs(context.StringSlice("args"))
+		if err := g.SetProcessArgs(context.StringSlice("args")); err != nil {
+			return err
+		}
 	}
 
 	{
@@ -520,7 +566,9 @@ func setupSpec(g *generate.Generator, context *cli.Context) error {
 			if err := json.Unmarshal([]byte(mount), &mnt); err != nil {
 				return err
 			}
-			g.AddMount(mnt)
+			if err := g.AddMount(mnt); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -712,6 +760,12 @@ func setupSpec(g *generate.Generator, context *cli.Context) error {
 		g.SetLinuxResourcesCPUQuota(context.Int64("linux-cpu-quota"))
 	}
 
+	if context.IsSet("linux-cpu-burst") {
+		if err := g.SetLinuxResourcesCPUBurst(context.Uint64("linux-cpu-burst")); err != nil {
+			return err
+		}
+	}
+
 	if context.IsSet("linux-realtime-runtime") {
 		g.SetLinuxResourcesCPURealtimeRuntime(context.Int64("linux-realtime-runtime"))
 	}
@@ -759,23 +813,43 @@ func setupSpec(g *generate.Generator, context *cli.Context) error {
 	}
 
 	if context.IsSet("linux-mem-limit") {
-		g.SetLinuxResourcesMemoryLimit(context.Int64("linux-mem-limit"))
+		size, err := generate.ParseMemoryString(context.String("linux-mem-limit"))
+		if err != nil {
+			return err
+		}
+		g.SetLinuxResourcesMemoryLimit(size)
 	}
 
 	if context.IsSet("linux-mem-reservation") {
-		g.SetLinuxResourcesMemoryReservation(context.Int64("linux-mem-reservation"))
+		size, err := generate.ParseMemoryString(context.String("linux-mem-reservation"))
+		if err != nil {
+			return err
+		}
+		g.SetLinuxResourcesMemoryReservation(size)
 	}
 
 	if context.IsSet("linux-mem-swap") {
-		g.SetLinuxResourcesMemorySwap(context.Int64("linux-mem-swap"))
+		size, err := generate.ParseMemoryString(context.String("linux-mem-swap"))
+		if err != nil {
+			return err
+		}
+		g.SetLinuxResourcesMemorySwap(size)
 	}
 
 	if context.IsSet("linux-mem-kernel-limit") {
-		g.SetLinuxResourcesMemoryKernel(context.Int64("linux-mem-kernel-limit"))
+		size, err := generate.ParseMemoryString(context.String("linux-mem-kernel-limit"))
+		if err != nil {
+			return err
+		}
+		g.SetLinuxResourcesMemoryKernel(size)
 	}
 
 	if context.IsSet("linux-mem-kernel-tcp") {
-		g.SetLinuxResourcesMemoryKernelTCP(context.Int64("linux-mem-kernel-tcp"))
+		size, err := generate.ParseMemoryString(context.String("linux-mem-kernel-tcp"))
+		if err != nil {
+			return err
+		}
+		g.SetLinuxResourcesMemoryKernelTCP(size)
 	}
 
 	if context.IsSet("linux-mem-swappiness") {
@@ -7,12 +7,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/mndrix/tap-go"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
@@ -23,6 +27,7 @@ import (
 	"github.com/opencontainers/runtime-tools/cmd/runtimetest/mount"
 	rfc2119 "github.com/opencontainers/runtime-tools/error"
 	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/selinux/go-selinux"
 	"github.com/opencontainers/selinux/go-selinux/label"
 
 	"golang.org/x/sys/unix"
@@ -180,10 +185,76 @@ func (c *complianceTester) validatePosixUser(spec *rspec.Spec) error {
 		groupsMap[g] = true
 	}
 
+	// The supplementary groups may include the primary group, but the
+	// primary group must not be taken from additionalGids.
+	var missing []uint32
+	confused := false
 	for _, g := range spec.Process.User.AdditionalGids {
-		c.harness.Ok(groupsMap[int(g)], fmt.Sprintf("has expected additional group ID %v", g))
+		if !groupsMap[int(g)] {
+			missing = append(missing, g)
+		}
+		if g == gid && gid != spec.Process.User.GID {
+			confused = true
+		}
+	}
+	c.harness.Ok(len(missing) == 0 && !confused, "has gid as the primary group and additionalGids as supplementary groups")
+	_ = c.harness.YAML(map[string]interface{}{
+		"expected": map[string]interface{}{
+			"gid":            spec.Process.User.GID,
+			"additionalGids": spec.Process.User.AdditionalGids,
+		},
+		"actual": map[string]interface{}{
+			"gid":    gid,
+			"groups": groups,
+		},
+	})
+
+	return nil
+}
+
+func (c *complianceTester) validateUmask(spec *rspec.Spec) error {
+	if spec.Process == nil || spec.Process.User.Umask == nil {
+		c.harness.Skip(1, "process.user.umask not set")
+		return nil
+	}
+	umask := os.FileMode(*spec.Process.User.Umask)
+
+	dir, err := os.MkdirTemp("", "runtimetest-umask")
+	if err != nil {
+		return err
 	}
+	defer os.RemoveAll(dir)
 
+	file := filepath.Join(dir, "file")
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o666)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	subdir := filepath.Join(dir, "dir")
+	if err := os.Mkdir(subdir, 0o777); err != nil {
+		return err
+	}
+
+	for _, created := range []struct {
+		path string
+		mode os.FileMode
+	}{
+		{file, 0o666},
+		{subdir, 0o777},
+	} {
+		fi, err := os.Stat(created.path)
+		if err != nil {
+			return err
+		}
+		expected := created.mode &^ umask
+		actual := fi.Mode().Perm()
+		c.harness.Ok(actual == expected, fmt.Sprintf("%s created with mode %#o has umask %#o applied", filepath.Base(created.path), created.mode, umask))
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": fmt.Sprintf("%#o", expected),
+			"actual":   fmt.Sprintf("%#o", actual),
+		})
+	}
 	return nil
 }
 
@@ -207,10 +278,22 @@ func (c *complianceTester) validateProcess(spec *rspec.Spec) error {
 		})
 	}
 
+	// A later entry for a key overrides an earlier one, so the process
+	// should see the last value configured for each.
+	var keys []string
+	expected := make(map[string]string, len(spec.Process.Env))
 	for _, env := range spec.Process.Env {
-		parts := strings.Split(env, "=")
-		key := parts[0]
-		expectedValue := parts[1]
+		key, value, ok := strings.Cut(env, "=")
+		if !ok {
+			continue
+		}
+		if _, seen := expected[key]; !seen {
+			keys = append(keys, key)
+		}
+		expected[key] = value
+	}
+	for _, key := range keys {
+		expectedValue := expected[key]
 		actualValue := os.Getenv(key)
 		c.harness.Ok(expectedValue == actualValue, fmt.Sprintf("has expected environment variable %v", key))
 		_ = c.harness.YAML(map[string]string{
@@ -223,6 +306,174 @@ func (c *complianceTester) validateProcess(spec *rspec.Spec) error {
 	return nil
 }
 
+// signalMasks returns the blocked and ignored signal masks from
+// /proc/self/status, with bit n-1 set for signal n.
+func signalMasks() (blocked, ignored uint64, err error) {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var foundBlk, foundIgn bool
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		key, value, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "SigBlk":
+			blocked, err = strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+			foundBlk = true
+		case "SigIgn":
+			ignored, err = strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+			foundIgn = true
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return 0, 0, err
+	}
+	if !foundBlk || !foundIgn {
+		return 0, 0, fmt.Errorf("SigBlk or SigIgn not found in /proc/self/status")
+	}
+	return blocked, ignored, nil
+}
+
+// annotationSignals is set by validation tests, to any value, when the
+// runtime was started with signals ignored that the container process should
+// not inherit.
+const annotationSignals = "com.github.opencontainers.runtime-tools.runtimetest.signals"
+
+func (c *complianceTester) validateSignalMask(spec *rspec.Spec) error {
+	if _, ok := spec.Annotations[annotationSignals]; !ok {
+		c.harness.Skip(1, "signal mask check not requested")
+		return nil
+	}
+	blocked, ignored, err := signalMasks()
+	if err != nil {
+		return err
+	}
+
+	// The Go runtime unblocks the signals it needs and installs handlers
+	// over inherited dispositions, except that it keeps SIGHUP and SIGINT
+	// ignored. So only termination signals are flagged as blocked, and only
+	// SIGHUP and SIGINT as ignored.
+	var unexpected []string
+	for _, sig := range []unix.Signal{unix.SIGHUP, unix.SIGINT, unix.SIGQUIT, unix.SIGTERM, unix.SIGUSR1, unix.SIGUSR2} {
+		if blocked&(1<<(uint(sig)-1)) != 0 {
+			unexpected = append(unexpected, fmt.Sprintf("%s blocked", unix.SignalName(sig)))
+		}
+	}
+	for _, sig := range []unix.Signal{unix.SIGHUP, unix.SIGINT} {
+		if ignored&(1<<(uint(sig)-1)) != 0 {
+			unexpected = append(unexpected, fmt.Sprintf("%s ignored", unix.SignalName(sig)))
+		}
+	}
+
+	c.harness.Ok(len(unexpected) == 0, "the process starts with no termination signals blocked or ignored")
+	_ = c.harness.YAML(map[string]interface{}{
+		"SigBlk":     fmt.Sprintf("%016x", blocked),
+		"SigIgn":     fmt.Sprintf("%016x", ignored),
+		"unexpected": unexpected,
+	})
+	return nil
+}
+
+// runtimeProvidedEnv are the variables a runtime may set itself when
+// process.env does not, such as runc setting HOME from the user database.
+var runtimeProvidedEnv = map[string]bool{
+	"HOME":      true,
+	"PATH":      true,
+	"TERM":      true,
+	"HOSTNAME":  true,
+	"container": true,
+}
+
+func (c *complianceTester) validateEmptyEnv(spec *rspec.Spec) error {
+	if spec.Process == nil || len(spec.Process.Env) > 0 {
+		c.harness.Skip(1, "process.env is not empty")
+		return nil
+	}
+
+	var leaked []string
+	for _, env := range os.Environ() {
+		key := strings.SplitN(env, "=", 2)[0]
+		if !runtimeProvidedEnv[key] {
+			leaked = append(leaked, env)
+		}
+	}
+
+	c.harness.Ok(len(leaked) == 0, "an empty process.env does not pass the host environment through")
+	_ = c.harness.YAML(map[string]interface{}{
+		"actual": os.Environ(),
+		"leaked": leaked,
+	})
+	return nil
+}
+
+// isTerminal reports whether fd refers to a terminal.
+func isTerminal(fd int) bool {
+	_, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	return err == nil
+}
+
+func (c *complianceTester) validateTerminal(spec *rspec.Spec) error {
+	if spec.Process == nil {
+		c.harness.Skip(1, "process not set")
+		return nil
+	}
+
+	for _, stream := range []struct {
+		name string
+		fd   int
+	}{
+		{"stdin", int(os.Stdin.Fd())},
+		{"stdout", int(os.Stdout.Fd())},
+	} {
+		tty := isTerminal(stream.fd)
+		var description string
+		if spec.Process.Terminal {
+			description = fmt.Sprintf("%s is a terminal", stream.name)
+		} else {
+			description = fmt.Sprintf("%s is not a terminal", stream.name)
+		}
+		c.harness.Ok(tty == spec.Process.Terminal, description)
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": spec.Process.Terminal,
+			"actual":   tty,
+		})
+	}
+
+	return nil
+}
+
+func (c *complianceTester) validateConsoleSize(spec *rspec.Spec) error {
+	if spec.Process == nil || !spec.Process.Terminal {
+		c.harness.Skip(1, "process.terminal not set")
+		return nil
+	}
+	if spec.Process.ConsoleSize == nil {
+		c.harness.Skip(1, "process.consoleSize not set")
+		return nil
+	}
+
+	ws, err := unix.IoctlGetWinsize(int(os.Stdin.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return err
+	}
+	expected := spec.Process.ConsoleSize
+	c.harness.Ok(uint(ws.Col) == expected.Width && uint(ws.Row) == expected.Height, "terminal has the configured size")
+	_ = c.harness.YAML(map[string]interface{}{
+		"expected": fmt.Sprintf("%dx%d", expected.Width, expected.Height),
+		"actual":   fmt.Sprintf("%dx%d", ws.Col, ws.Row),
+	})
+	return nil
+}
+
 func (c *complianceTester) validateLinuxProcess(spec *rspec.Spec) error {
 	if spec.Process == nil {
 		c.harness.Skip(1, "process not set")
@@ -234,20 +485,34 @@ func (c *complianceTester) validateLinuxProcess(spec *rspec.Spec) error {
 		return err
 	}
 
-	args := bytes.Split(bytes.Trim(cmdlineBytes, "\x00"), []byte("\x00"))
-	c.harness.Ok(len(args) == len(spec.Process.Args), "has expected number of process arguments")
-	_ = c.harness.YAML(map[string]interface{}{
-		"expected": spec.Process.Args,
-		"actual":   args,
-	})
-	for i, a := range args {
-		c.harness.Ok(string(a) == spec.Process.Args[i], fmt.Sprintf("has expected process argument %d", i))
-		_ = c.harness.YAML(map[string]interface{}{
-			"index":    i,
-			"expected": spec.Process.Args[i],
-			"actual":   string(a),
-		})
+	// Only the first differing argument is reported, as the list may be
+	// long enough for a truncation to be the whole point of the test.
+	args := strings.Split(strings.TrimSuffix(string(cmdlineBytes), "\x00"), "\x00")
+	mismatch := -1
+	for i := range spec.Process.Args {
+		if i >= len(args) || args[i] != spec.Process.Args[i] {
+			mismatch = i
+			break
+		}
+	}
+	if mismatch < 0 && len(args) > len(spec.Process.Args) {
+		mismatch = len(spec.Process.Args)
+	}
+	c.harness.Ok(mismatch < 0, fmt.Sprintf("has the %d expected process arguments", len(spec.Process.Args)))
+	argsYAML := map[string]interface{}{
+		"expected": len(spec.Process.Args),
+		"actual":   len(args),
+	}
+	if mismatch >= 0 {
+		argsYAML["index"] = mismatch
+		if mismatch < len(spec.Process.Args) {
+			argsYAML["expected argument"] = spec.Process.Args[mismatch]
+		}
+		if mismatch < len(args) {
+			argsYAML["actual argument"] = args[mismatch]
+		}
 	}
+	_ = c.harness.YAML(argsYAML)
 
 	ret, _, errno := syscall.Syscall6(syscall.SYS_PRCTL, PrGetNoNewPrivs, 0, 0, 0, 0, 0)
 	if errno != 0 {
@@ -328,6 +593,46 @@ func (c *complianceTester) validateCapabilities(spec *rspec.Spec) error {
 	return nil
 }
 
+func (c *complianceTester) validatePrivilegedMount(spec *rspec.Spec) error {
+	if spec.Process == nil || spec.Process.Capabilities == nil {
+		c.harness.Skip(1, "process.capabilities not set")
+		return nil
+	}
+	sysAdmin := false
+	for _, cap := range spec.Process.Capabilities.Effective {
+		if cap == "CAP_SYS_ADMIN" {
+			sysAdmin = true
+			break
+		}
+	}
+	if !sysAdmin {
+		c.harness.Skip(1, "CAP_SYS_ADMIN is not in process.capabilities.effective")
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "runtimetest-privileged")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dir)
+
+	mountErr := unix.Mount("tmpfs", dir, "tmpfs", 0, "")
+	if mountErr == nil {
+		if err := unix.Unmount(dir, 0); err != nil {
+			return err
+		}
+	}
+
+	c.harness.Ok(mountErr == nil, "CAP_SYS_ADMIN allows mounting a tmpfs")
+	if mountErr != nil {
+		_ = c.harness.YAML(map[string]interface{}{
+			"error": mountErr.Error(),
+		})
+	}
+
+	return nil
+}
+
 func (c *complianceTester) validateHostname(spec *rspec.Spec) error {
 	if spec.Hostname == "" {
 		c.harness.Skip(1, "hostname not set")
@@ -647,6 +952,84 @@ func (c *complianceTester) validateDefaultFS(spec *rspec.Spec) error {
 	return nil
 }
 
+// annotationProcMount is set by validation tests, to any value, to check
+// the type and flags of the /proc mount.
+const annotationProcMount = "com.github.opencontainers.runtime-tools.runtimetest.proc-mount"
+
+func (c *complianceTester) validateProcMount(spec *rspec.Spec) error {
+	if _, ok := spec.Annotations[annotationProcMount]; !ok {
+		c.harness.Skip(1, "/proc mount check not requested")
+		return nil
+	}
+	// Only the flags the config asks for are expected, so configs that
+	// intentionally relax the proc mount options are not flagged.
+	var expected []string
+	found := false
+	for _, m := range spec.Mounts {
+		if m.Destination != "/proc" {
+			continue
+		}
+		found = true
+		expected = nil
+		for _, opt := range m.Options {
+			switch opt {
+			case "nosuid", "nodev", "noexec":
+				expected = append(expected, opt)
+			}
+		}
+	}
+	if !found {
+		c.harness.Skip(1, "/proc is not in mounts")
+		return nil
+	}
+
+	mountInfos, err := mount.GetMounts()
+	if err != nil {
+		return err
+	}
+	var proc *mount.Info
+	for _, mountInfo := range mountInfos {
+		if mountInfo.Mountpoint == "/proc" {
+			proc = mountInfo
+		}
+	}
+	if proc == nil || proc.Fstype != "proc" {
+		fstype := ""
+		if proc != nil {
+			fstype = proc.Fstype
+		}
+		rfcError, err := c.Ok(false, specerror.DefaultFilesystems, spec.Version, "/proc is mounted as proc")
+		if err != nil {
+			return err
+		}
+		_ = c.harness.YAML(map[string]string{
+			"level":     rfcError.Level.String(),
+			"reference": rfcError.Reference,
+			"expected":  "proc",
+			"actual":    fstype,
+		})
+		return nil
+	}
+
+	opts := strings.Split(proc.Opts, ",")
+	for _, flag := range expected {
+		set := false
+		for _, opt := range opts {
+			if opt == flag {
+				set = true
+				break
+			}
+		}
+		c.harness.Ok(set, fmt.Sprintf("/proc is mounted with %s", flag))
+		_ = c.harness.YAML(map[string]string{
+			"expected": flag,
+			"actual":   proc.Opts,
+		})
+	}
+
+	return nil
+}
+
 func (c *complianceTester) validateLinuxDevices(spec *rspec.Spec) error {
 	if spec.Linux == nil || spec.Linux.Devices == nil {
 		c.harness.Skip(1, "linux.devices is not set")
@@ -667,6 +1050,98 @@ func (c *complianceTester) validateLinuxDevices(spec *rspec.Spec) error {
 	return nil
 }
 
+// defaultDeviceNumbers are the major:minor numbers of the default devices,
+// which runtimes allow in the device cgroup whatever the configured rules.
+var defaultDeviceNumbers = map[[2]int64]bool{
+	{1, 3}: true, // /dev/null
+	{1, 5}: true, // /dev/zero
+	{1, 7}: true, // /dev/full
+	{1, 8}: true, // /dev/random
+	{1, 9}: true, // /dev/urandom
+	{5, 0}: true, // /dev/tty
+	{5, 1}: true, // /dev/console
+	{5, 2}: true, // /dev/ptmx
+}
+
+// deviceReadAllowed evaluates the device cgroup rules for read access to
+// device. The last matching rule wins; matched is false if no rule matches.
+// The default character devices are always allowed.
+func deviceReadAllowed(rules []rspec.LinuxDeviceCgroup, device rspec.LinuxDevice) (allowed bool, matched bool) {
+	if device.Type != "b" && defaultDeviceNumbers[[2]int64{device.Major, device.Minor}] {
+		return true, true
+	}
+	for _, rule := range rules {
+		if rule.Type != "" && rule.Type != "a" && rule.Type != device.Type {
+			continue
+		}
+		if rule.Major != nil && *rule.Major != device.Major {
+			continue
+		}
+		if rule.Minor != nil && *rule.Minor != device.Minor {
+			continue
+		}
+		if rule.Access != "" && !strings.Contains(rule.Access, "r") {
+			continue
+		}
+		allowed, matched = rule.Allow, true
+	}
+	return allowed, matched
+}
+
+// validateDeviceCgroup checks device cgroup rules by opening the devices
+// from linux.devices. This is behavioral, so it works the same way for the
+// cgroup v1 devices controller and the eBPF programs used on cgroup v2.
+func (c *complianceTester) validateDeviceCgroup(spec *rspec.Spec) error {
+	if spec.Linux == nil || spec.Linux.Resources == nil || len(spec.Linux.Resources.Devices) == 0 {
+		c.harness.Skip(1, "linux.resources.devices not set")
+		return nil
+	}
+	if len(spec.Linux.Devices) == 0 {
+		c.harness.Skip(1, "linux.devices not set")
+		return nil
+	}
+
+	for _, device := range spec.Linux.Devices {
+		if device.Type != "c" && device.Type != "b" && device.Type != "u" {
+			continue
+		}
+		allowed, matched := deviceReadAllowed(spec.Linux.Resources.Devices, device)
+		if !matched {
+			c.harness.Skip(1, fmt.Sprintf("no linux.resources.devices rule matches %s", device.Path))
+			continue
+		}
+
+		f, err := os.OpenFile(device.Path, os.O_RDONLY|unix.O_NONBLOCK, 0)
+		if err == nil {
+			f.Close()
+		}
+		denied := errors.Is(err, unix.EPERM)
+
+		var description string
+		if allowed {
+			description = fmt.Sprintf("device %s is allowed by the device cgroup", device.Path)
+		} else {
+			description = fmt.Sprintf("device %s is denied by the device cgroup", device.Path)
+		}
+		rfcError, rerr := c.Ok(allowed != denied, specerror.DevicesApplyInOrder, spec.Version, description)
+		if rerr != nil {
+			return rerr
+		}
+		actual := "opened"
+		if err != nil {
+			actual = err.Error()
+		}
+		_ = c.harness.YAML(map[string]interface{}{
+			"level":     rfcError.Level.String(),
+			"reference": rfcError.Reference,
+			"allowed":   allowed,
+			"actual":    actual,
+		})
+	}
+
+	return nil
+}
+
 func (c *complianceTester) validateDevice(device *rspec.LinuxDevice, condition specerror.Code, version string, description string) (err error) {
 	var exists bool
 	fi, err := os.Stat(device.Path)
@@ -917,6 +1392,45 @@ func (c *complianceTester) validateMaskedPaths(spec *rspec.Spec) error {
 	return nil
 }
 
+// seccompChmod changes the mode of a temporary file, which goes through
+// fchmodat(2), and returns the error from that call.
+func seccompChmod() error {
+	f, err := os.CreateTemp("", "runtimetest-seccomp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return unix.Fchmodat(unix.AT_FDCWD, f.Name(), 0o600, 0)
+}
+
+// seccompSethostname sets the hostname to its current value through
+// sethostname(2), which needs CAP_SYS_ADMIN, and returns the error from that
+// call.
+func seccompSethostname() error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	return unix.Sethostname([]byte(hostname))
+}
+
+// seccompProbes make the syscall they are keyed by, for checking the errno
+// seccomp rules return.
+var seccompProbes = map[string]func() error{
+	"fchmodat":    seccompChmod,
+	"sethostname": seccompSethostname,
+}
+
+func errnoString(errno syscall.Errno) string {
+	if errno == 0 {
+		return "success"
+	}
+	return errno.Error()
+}
+
 func (c *complianceTester) validateSeccomp(spec *rspec.Spec) error {
 	if spec.Linux == nil || spec.Linux.Seccomp == nil {
 		c.harness.Skip(1, "linux.seccomp not set")
@@ -931,6 +1445,25 @@ func (c *complianceTester) validateSeccomp(spec *rspec.Spec) error {
 					if err == nil {
 						c.harness.Skip(1, "getcwd did not return an error")
 					}
+				} else if probe, ok := seccompProbes[name]; ok {
+					expected := unix.EPERM
+					if sys.ErrnoRet != nil {
+						expected = syscall.Errno(*sys.ErrnoRet)
+					}
+					var errno syscall.Errno
+					if err := probe(); !errors.As(err, &errno) && err != nil {
+						return err
+					}
+					rfcError, err := c.Ok(errno == expected, specerror.SeccSyscallsErrnoRet, spec.Version, fmt.Sprintf("%s syscall returns %v", name, expected))
+					if err != nil {
+						return err
+					}
+					_ = c.harness.YAML(map[string]interface{}{
+						"level":     rfcError.Level.String(),
+						"reference": rfcError.Reference,
+						"expected":  expected.Error(),
+						"actual":    errnoString(errno),
+					})
 				} else {
 					c.harness.Skip(1, fmt.Sprintf("%s syscall returns errno", name))
 				}
@@ -1250,13 +1783,1076 @@ func (c *complianceTester) validateMountLabel(spec *rspec.Spec) error {
 	return nil
 }
 
-func run(context *cli.Context) error {
-	logLevelString := context.String("log-level")
-	logLevel, err := logrus.ParseLevel(logLevelString)
-	if err != nil {
-		return err
+// idMapped reports whether id falls inside the host range of one of mappings.
+func idMapped(id uint32, mappings []rspec.LinuxIDMapping) bool {
+	for _, m := range mappings {
+		if id >= m.HostID && id-m.HostID < m.Size {
+			return true
+		}
+	}
+	return false
+}
+
+// annotationOwners is set by validation tests to a JSON object mapping paths
+// in the container to the {"uid", "gid"} they are expected to be owned by.
+const annotationOwners = "com.github.opencontainers.runtime-tools.runtimetest.owners"
+
+type owner struct {
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+}
+
+func (c *complianceTester) validateRootfsOwnership(spec *rspec.Spec) error {
+	data, ok := spec.Annotations[annotationOwners]
+	if !ok {
+		c.harness.Skip(1, "no expected owners set")
+		return nil
+	}
+	var owners map[string]owner
+	if err := json.Unmarshal([]byte(data), &owners); err != nil {
+		return fmt.Errorf("%s: %w", annotationOwners, err)
+	}
+
+	paths := make([]string, 0, len(owners))
+	for path := range owners {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		var st unix.Stat_t
+		if err := unix.Lstat(path, &st); err != nil {
+			return err
+		}
+		expected := owners[path]
+		actual := owner{UID: st.Uid, GID: st.Gid}
+		c.harness.Ok(actual == expected, fmt.Sprintf("%s is owned by the container IDs its host owner maps to", path))
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+	return nil
+}
+
+func (c *complianceTester) validateNetworkNamespaceInterfaces(spec *rspec.Spec) error {
+	if spec.Linux == nil {
+		c.harness.Skip(1, "linux not set")
+		return nil
+	}
+
+	var netns *rspec.LinuxNamespace
+	for i, ns := range spec.Linux.Namespaces {
+		if ns.Type == rspec.NetworkNamespace {
+			netns = &spec.Linux.Namespaces[i]
+			break
+		}
+	}
+	if netns == nil {
+		c.harness.Skip(1, "linux.namespaces does not include a network namespace")
+		return nil
+	}
+	if netns.Path != "" {
+		c.harness.Skip(1, "network namespace is joined by path, so its interfaces are shared")
+		return nil
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, iface := range interfaces {
+		names = append(names, iface.Name)
+	}
+
+	rfcError, err := c.Ok(len(names) == 1 && names[0] == "lo", specerror.NSNewNSWithoutPath, spec.Version, "only the loopback interface is visible in a new network namespace")
+	if err != nil {
+		return err
+	}
+	_ = c.harness.YAML(map[string]interface{}{
+		"level":     rfcError.Level.String(),
+		"reference": rfcError.Reference,
+		"expected":  []string{"lo"},
+		"actual":    names,
+	})
+
+	return nil
+}
+
+// parseCPUList parses a cpuset list such as "0-2,4" into the CPUs it names,
+// in ascending order.
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	for _, part := range strings.Split(list, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu list %q: %w", list, err)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid cpu list %q: %w", list, err)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	sort.Ints(cpus)
+	unique := cpus[:0]
+	for i, cpu := range cpus {
+		if i == 0 || cpu != cpus[i-1] {
+			unique = append(unique, cpu)
+		}
+	}
+	return unique, nil
+}
+
+func (c *complianceTester) validateCPUAffinity(spec *rspec.Spec) error {
+	if spec.Linux == nil || spec.Linux.Resources == nil || spec.Linux.Resources.CPU == nil || spec.Linux.Resources.CPU.Cpus == "" {
+		c.harness.Skip(1, "linux.resources.cpu.cpus not set")
+		return nil
+	}
+
+	expected, err := parseCPUList(spec.Linux.Resources.CPU.Cpus)
+	if err != nil {
+		return err
+	}
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return err
+	}
+
+	var actual []int
+	for cpu := 0; cpu < len(set)*64; cpu++ {
+		if set.IsSet(cpu) {
+			actual = append(actual, cpu)
+		}
+	}
+	match := len(actual) == len(expected)
+	for i := 0; match && i < len(actual); i++ {
+		match = actual[i] == expected[i]
+	}
+
+	c.harness.Ok(match, "the process may only run on linux.resources.cpu.cpus")
+	_ = c.harness.YAML(map[string]interface{}{
+		"expected": expected,
+		"actual":   actual,
+	})
+
+	// nproc and sched_getaffinity users see the cpuset through the affinity
+	// count. /proc/cpuinfo still lists every CPU of the host, since the
+	// kernel does not filter it by cpuset, so it is not checked.
+	c.harness.Ok(set.Count() == len(expected), "the process sees as many CPUs as linux.resources.cpu.cpus lists")
+	_ = c.harness.YAML(map[string]interface{}{
+		"expected": len(expected),
+		"actual":   set.Count(),
+	})
+	return nil
+}
+
+// annotationHostPID is set by validation tests to the PID, in the runtime's
+// PID namespace, of a process the container should see when it does not
+// get a PID namespace of its own.
+const annotationHostPID = "com.github.opencontainers.runtime-tools.runtimetest.host-pid"
+
+func (c *complianceTester) validateHostPIDNamespace(spec *rspec.Spec) error {
+	if spec.Linux == nil {
+		c.harness.Skip(1, "linux not set")
+		return nil
+	}
+	for _, ns := range spec.Linux.Namespaces {
+		if ns.Type == rspec.PIDNamespace {
+			c.harness.Skip(1, "linux.namespaces has a PID namespace")
+			return nil
+		}
+	}
+	data, ok := spec.Annotations[annotationHostPID]
+	if !ok {
+		c.harness.Skip(1, "no host PID set")
+		return nil
+	}
+	pid, err := strconv.Atoi(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", annotationHostPID, err)
+	}
+
+	// A new PID namespace would make this process pid 1 and hide the
+	// processes outside the container from /proc.
+	_, err = os.Stat(filepath.Join("/proc", strconv.Itoa(pid)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	visible := err == nil
+	self := os.Getpid()
+	rfcError, err := c.Ok(visible && self != 1, specerror.NSInheritWithoutType, spec.Version, "inherits the PID namespace of the runtime")
+	if err != nil {
+		return err
+	}
+	_ = c.harness.YAML(map[string]interface{}{
+		"level":     rfcError.Level.String(),
+		"reference": rfcError.Reference,
+		"host pid":  pid,
+		"visible":   visible,
+		"self":      self,
+	})
+	return nil
+}
+
+// procNamespaceNames maps namespace types to their names under /proc/*/ns.
+var procNamespaceNames = map[rspec.LinuxNamespaceType]string{
+	rspec.PIDNamespace:     "pid",
+	rspec.NetworkNamespace: "net",
+	rspec.MountNamespace:   "mnt",
+	rspec.IPCNamespace:     "ipc",
+	rspec.UTSNamespace:     "uts",
+	rspec.UserNamespace:    "user",
+	rspec.CgroupNamespace:  "cgroup",
+	rspec.TimeNamespace:    "time",
+}
+
+// validateExecNamespaces checks, for a runtimetest run with exec, that it is
+// in the namespaces of the container process, which is pid 1 there.
+func (c *complianceTester) validateExecNamespaces(spec *rspec.Spec) error {
+	if spec.Linux == nil {
+		c.harness.Skip(1, "linux not set")
+		return nil
+	}
+	pidns := false
+	for _, ns := range spec.Linux.Namespaces {
+		if ns.Type == rspec.PIDNamespace {
+			pidns = true
+			break
+		}
+	}
+	if !pidns {
+		c.harness.Skip(1, "the container process is only pid 1 in a new PID namespace")
+		return nil
+	}
+
+	for _, ns := range spec.Linux.Namespaces {
+		name, ok := procNamespaceNames[ns.Type]
+		if !ok {
+			continue
+		}
+		expected, err := os.Readlink(filepath.Join("/proc/1/ns", name))
+		if err != nil {
+			return err
+		}
+		actual, err := os.Readlink(filepath.Join("/proc/self/ns", name))
+		if err != nil {
+			return err
+		}
+		c.harness.Ok(actual == expected, fmt.Sprintf("exec joins the %s namespace of the container process", ns.Type))
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+	return nil
+}
+
+// allocate touches size bytes of anonymous memory, one byte per page, so
+// all of it is charged to the memory cgroup.
+func allocate(size int64) {
+	buf := make([]byte, size)
+	for i := 0; i < len(buf); i += os.Getpagesize() {
+		buf[i] = 1
+	}
+}
+
+func (c *complianceTester) validateMemoryLimit(spec *rspec.Spec) error {
+	if spec.Linux == nil || spec.Linux.Resources == nil || spec.Linux.Resources.Memory == nil ||
+		spec.Linux.Resources.Memory.Limit == nil || *spec.Linux.Resources.Memory.Limit <= 0 {
+		c.harness.Skip(1, "linux.resources.memory.limit not set")
+		return nil
+	}
+	memory := spec.Linux.Resources.Memory
+	if memory.DisableOOMKiller != nil && *memory.DisableOOMKiller {
+		c.harness.Skip(1, "the OOM killer is disabled, so allocating beyond the limit would hang")
+		return nil
+	}
+	if memory.Swap == nil || *memory.Swap != *memory.Limit {
+		c.harness.Skip(1, "linux.resources.memory.swap does not match the limit, so swap may absorb the allocation")
+		return nil
+	}
+
+	// Allocate twice the limit plus a margin in a child, which the kernel
+	// should OOM-kill, so that accounting slack cannot let it fit.
+	size := 2**memory.Limit + 16<<20
+	err := exec.Command("/proc/self/exe", fmt.Sprintf("--allocate=%d", size)).Run()
+	c.harness.Ok(err != nil, "allocating beyond linux.resources.memory.limit fails")
+	actual := "allocation succeeded"
+	if err != nil {
+		actual = err.Error()
+	}
+	_ = c.harness.YAML(map[string]interface{}{
+		"limit":     *memory.Limit,
+		"allocated": size,
+		"actual":    actual,
+	})
+	return nil
+}
+
+// processState returns the state letter from /proc/<pid>/stat, or 0 if there
+// is no such process.
+func processState(pid int) (byte, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	// The state follows the command name, which is in parentheses and may
+	// itself contain spaces or parentheses.
+	stat := string(data)
+	i := strings.LastIndex(stat, ")")
+	if i < 0 || i+2 >= len(stat) {
+		return 0, fmt.Errorf("pid %d: malformed stat %q", pid, stat)
+	}
+	return stat[i+2], nil
+}
+
+// annotationReapOrphans is set by validation tests, to any value, to check
+// that the init of a new PID namespace reaps the processes orphaned to it.
+const annotationReapOrphans = "com.github.opencontainers.runtime-tools.runtimetest.reap-orphans"
+
+func (c *complianceTester) validateOrphansReaped(spec *rspec.Spec) error {
+	if _, ok := spec.Annotations[annotationReapOrphans]; !ok {
+		c.harness.Skip(1, "orphan reaping not requested")
+		return nil
+	}
+	if spec.Linux == nil {
+		c.harness.Skip(1, "linux not set")
+		return nil
+	}
+
+	var pidns *rspec.LinuxNamespace
+	for i, ns := range spec.Linux.Namespaces {
+		if ns.Type == rspec.PIDNamespace {
+			pidns = &spec.Linux.Namespaces[i]
+			break
+		}
+	}
+	if pidns == nil || pidns.Path != "" {
+		c.harness.Skip(1, "linux.namespaces does not include a new PID namespace")
+		return nil
+	}
+	if os.Getpid() == 1 {
+		c.harness.Skip(1, "runtimetest is the init of the PID namespace, so reaping orphans is up to it")
+		return nil
+	}
+
+	// The shell exits while its background child is still running, leaving
+	// the child to be reparented to the init of the PID namespace, which has
+	// to reap it once it exits in turn.
+	out, err := exec.Command("sh", "-c", "sleep 1 & echo $!").Output()
+	if err != nil {
+		return err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return fmt.Errorf("orphan pid: %w", err)
+	}
+	var state byte
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(100 * time.Millisecond) {
+		state, err = processState(pid)
+		if err != nil {
+			return err
+		}
+		if state == 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	actual := "reaped"
+	if state != 0 {
+		actual = fmt.Sprintf("left in state %c", state)
+	}
+	c.harness.Ok(state == 0, "an orphaned process is reaped by the init of a new PID namespace")
+	_ = c.harness.YAML(map[string]interface{}{
+		"pid":    pid,
+		"actual": actual,
+	})
+
+	return nil
+}
+
+// pathWithin reports whether path is dir or lies beneath it.
+func pathWithin(path, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	return path == dir || dir == "/" || strings.HasPrefix(path, dir+"/")
+}
+
+// annotationMountIsolation is set by validation tests, to any value, when
+// every mount in the container besides the rootfs comes from the config.
+const annotationMountIsolation = "com.github.opencontainers.runtime-tools.runtimetest.mount-isolation"
+
+func (c *complianceTester) validateMountNamespaceIsolation(spec *rspec.Spec) error {
+	if _, ok := spec.Annotations[annotationMountIsolation]; !ok {
+		c.harness.Skip(1, "mount isolation not requested")
+		return nil
+	}
+	if spec.Linux == nil {
+		c.harness.Skip(1, "linux not set")
+		return nil
+	}
+
+	var mntns *rspec.LinuxNamespace
+	for i, ns := range spec.Linux.Namespaces {
+		if ns.Type == rspec.MountNamespace {
+			mntns = &spec.Linux.Namespaces[i]
+			break
+		}
+	}
+	if mntns == nil {
+		c.harness.Skip(1, "linux.namespaces does not include a mount namespace")
+		return nil
+	}
+	if mntns.Path != "" {
+		c.harness.Skip(1, "mount namespace is joined by path, so its mounts are shared")
+		return nil
+	}
+
+	// Besides the root filesystem, the runtime may mount the configured
+	// mounts (and anything beneath them, such as the submounts of an rbind),
+	// masked and readonly paths, devices and the console.
+	var allowed []string
+	for _, m := range spec.Mounts {
+		if m.Destination != "/" {
+			allowed = append(allowed, m.Destination)
+		}
+	}
+	allowed = append(allowed, spec.Linux.MaskedPaths...)
+	allowed = append(allowed, spec.Linux.ReadonlyPaths...)
+	for _, d := range spec.Linux.Devices {
+		allowed = append(allowed, d.Path)
+	}
+	allowed = append(allowed, "/dev/console")
+
+	mountInfos, err := mount.GetMounts()
+	if err != nil {
+		return err
+	}
+	var unexpected []string
+	for _, info := range mountInfos {
+		if info.Mountpoint == "/" {
+			continue
+		}
+		found := false
+		for _, dir := range allowed {
+			if pathWithin(info.Mountpoint, dir) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unexpected = append(unexpected, info.Mountpoint)
+		}
+	}
+
+	c.harness.Ok(len(unexpected) == 0, "no host mounts are visible in a new mount namespace")
+	_ = c.harness.YAML(map[string]interface{}{
+		"unexpected": unexpected,
+	})
+
+	return nil
+}
+
+func (c *complianceTester) validateMountLabelFileContext(spec *rspec.Spec) error {
+	if spec.Linux == nil || spec.Linux.MountLabel == "" {
+		c.harness.Skip(1, "linux.mountlabel not set")
+		return nil
+	}
+	if !selinux.GetEnabled() {
+		c.harness.Skip(1, "SELinux is not enabled")
+		return nil
+	}
+
+	// Files created on a labeled tmpfs inherit the mount's context.
+	var dir string
+	for _, mount := range spec.Mounts {
+		if mount.Type != "tmpfs" {
+			continue
+		}
+		readonly := false
+		for _, opt := range mount.Options {
+			if opt == "ro" {
+				readonly = true
+				break
+			}
+		}
+		if !readonly {
+			dir = mount.Destination
+			break
+		}
+	}
+	if dir == "" {
+		c.harness.Skip(1, "no writable tmpfs mount to create a file on")
+		return nil
+	}
+
+	f, err := os.CreateTemp(dir, "runtimetest-label")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 256)
+	n, err := unix.Lgetxattr(f.Name(), "security.selinux", buf)
+	if err != nil {
+		return fmt.Errorf("failed to get the SELinux context of %v: %w", f.Name(), err)
+	}
+	fileLabel := strings.TrimRight(string(buf[:n]), "\x00")
+
+	c.harness.Ok(fileLabel == spec.Linux.MountLabel, fmt.Sprintf("a file created under %s has the mountLabel context", dir))
+	_ = c.harness.YAML(map[string]interface{}{
+		"expected": spec.Linux.MountLabel,
+		"actual":   fileLabel,
+	})
+
+	return nil
+}
+
+func (c *complianceTester) validateMountIDMappings(spec *rspec.Spec) error {
+	var found bool
+	for _, m := range spec.Mounts {
+		if len(m.UIDMappings) > 0 || len(m.GIDMappings) > 0 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.harness.Skip(1, "no idmapped mounts set")
+		return nil
+	}
+
+	if spec.Linux != nil {
+		for _, ns := range spec.Linux.Namespaces {
+			if ns.Type == rspec.UserNamespace {
+				c.harness.Skip(1, "idmapped mount ownership is not checked inside a user namespace")
+				return nil
+			}
+		}
+	}
+
+	for i, m := range spec.Mounts {
+		if len(m.UIDMappings) == 0 && len(m.GIDMappings) == 0 {
+			continue
+		}
+
+		fi, err := os.Stat(m.Destination)
+		if err != nil {
+			return err
+		}
+		fStat, ok := fi.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fmt.Errorf("could not convert to syscall.Stat_t: %v", fi.Sys())
+		}
+
+		if len(m.UIDMappings) > 0 {
+			c.harness.Ok(idMapped(fStat.Uid, m.UIDMappings), fmt.Sprintf("mounts[%d] (%s) has an owner mapped by uidMappings", i, m.Destination))
+			_ = c.harness.YAML(map[string]interface{}{
+				"expected": m.UIDMappings,
+				"actual":   fStat.Uid,
+			})
+		}
+
+		if len(m.GIDMappings) > 0 {
+			c.harness.Ok(idMapped(fStat.Gid, m.GIDMappings), fmt.Sprintf("mounts[%d] (%s) has a group mapped by gidMappings", i, m.Destination))
+			_ = c.harness.YAML(map[string]interface{}{
+				"expected": m.GIDMappings,
+				"actual":   fStat.Gid,
+			})
+		}
+	}
+
+	return nil
+}
+
+// annotationSysfsReadonly is set by validation tests, to any value, to check
+// that a read-only /sys mount rejects writes.
+const annotationSysfsReadonly = "com.github.opencontainers.runtime-tools.runtimetest.sysfs-readonly"
+
+func (c *complianceTester) validateSysfsReadonly(spec *rspec.Spec) error {
+	if _, ok := spec.Annotations[annotationSysfsReadonly]; !ok {
+		c.harness.Skip(1, "read-only /sys check not requested")
+		return nil
+	}
+	return c.validateReadonlyMount(spec, "/sys", "sysfs")
+}
+
+func (c *complianceTester) validateCgroupReadonly(spec *rspec.Spec) error {
+	return c.validateReadonlyMount(spec, "/sys/fs/cgroup", "cgroup", "cgroup2")
+}
+
+// validateReadonlyMount checks that a mount of one of types on destination,
+// which the default config mounts read-only, is read-only when the config
+// says so. Privileged configs may make it writable with "rw", which is
+// skipped.
+func (c *complianceTester) validateReadonlyMount(spec *rspec.Spec, destination string, types ...string) error {
+	for i, m := range spec.Mounts {
+		if filepath.Clean(m.Destination) != destination {
+			continue
+		}
+		typeMatch := false
+		for _, t := range types {
+			if m.Type == t {
+				typeMatch = true
+				break
+			}
+		}
+		if !typeMatch {
+			continue
+		}
+		var ro bool
+		for _, o := range m.Options {
+			switch o {
+			case "ro":
+				ro = true
+			case "rw":
+				ro = false
+			}
+		}
+		if !ro {
+			c.harness.Skip(1, fmt.Sprintf("mounts[%d] (%s) is writable by configuration", i, m.Destination))
+			return nil
+		}
+
+		// access(2) reports EROFS for a write check on a read-only mount
+		// before looking at permissions, so nothing under it is touched.
+		err := unix.Access(m.Destination, unix.W_OK)
+		c.harness.Ok(errors.Is(err, syscall.EROFS), fmt.Sprintf("mounts[%d] (%s) is a read-only %s", i, m.Destination, m.Type))
+		actual := "writable"
+		if err != nil {
+			actual = err.Error()
+		}
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": syscall.EROFS.Error(),
+			"actual":   actual,
+		})
+		return nil
+	}
+
+	c.harness.Skip(1, fmt.Sprintf("no %s mount on %s", strings.Join(types, " or "), destination))
+	return nil
+}
+
+// tmpfsSize parses the value of a tmpfs size option into bytes. Sizes given
+// as a percentage of memory are reported as not ok.
+func tmpfsSize(size string) (bytes uint64, ok bool) {
+	var mult uint64 = 1
+	if size != "" {
+		switch size[len(size)-1] {
+		case 'k', 'K':
+			mult = 1 << 10
+		case 'm', 'M':
+			mult = 1 << 20
+		case 'g', 'G':
+			mult = 1 << 30
+		}
+		if mult != 1 {
+			size = size[:len(size)-1]
+		}
+	}
+	n, err := strconv.ParseUint(size, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * mult, true
+}
+
+// annotationDestinations returns the mount destinations listed,
+// comma-separated, in the key annotation, and whether it is set at all.
+func annotationDestinations(spec *rspec.Spec, key string) (map[string]bool, bool) {
+	data, ok := spec.Annotations[key]
+	if !ok {
+		return nil, false
+	}
+	dests := map[string]bool{}
+	for _, dest := range strings.Split(data, ",") {
+		dests[filepath.Clean(dest)] = true
+	}
+	return dests, true
+}
+
+// annotationTmpfsSize is set by validation tests to the tmpfs mount
+// destinations whose size option should be checked.
+const annotationTmpfsSize = "com.github.opencontainers.runtime-tools.runtimetest.tmpfs-size"
+
+func (c *complianceTester) validateTmpfsSize(spec *rspec.Spec) error {
+	dests, ok := annotationDestinations(spec, annotationTmpfsSize)
+	if !ok {
+		c.harness.Skip(1, "no tmpfs sizes to check")
+		return nil
+	}
+	var found bool
+	for i, m := range spec.Mounts {
+		if m.Type != "tmpfs" || !dests[filepath.Clean(m.Destination)] {
+			continue
+		}
+		var expected uint64
+		var sized bool
+		for _, o := range m.Options {
+			if strings.HasPrefix(o, "size=") {
+				expected, sized = tmpfsSize(strings.TrimPrefix(o, "size="))
+			}
+		}
+		if !sized {
+			continue
+		}
+		found = true
+
+		// tmpfs rounds its size up to whole pages.
+		page := uint64(os.Getpagesize())
+		expected = (expected + page - 1) / page * page
+
+		var st unix.Statfs_t
+		if err := unix.Statfs(m.Destination, &st); err != nil {
+			return err
+		}
+		actual := uint64(st.Blocks) * uint64(st.Bsize)
+		c.harness.Ok(actual == expected, fmt.Sprintf("mounts[%d] (%s) has the configured size", i, m.Destination))
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+	if !found {
+		c.harness.Skip(1, "no tmpfs mounts with a size")
+	}
+	return nil
+}
+
+// execFrom copies the runtimetest binary into dir and runs the copy, which
+// only prints its version. The returned error is nil if the copy ran.
+// created is false if the copy could not be put in dir at all.
+func execFrom(dir string) (created bool, err error) {
+	src, err := os.Open("/proc/self/exe")
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+	dst, err := os.CreateTemp(dir, "runtimetest-exec")
+	if err != nil {
+		return false, nil
+	}
+	defer os.Remove(dst.Name())
+	_, err = io.Copy(dst, src)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(dst.Name(), 0o755)
+	}
+	if err != nil {
+		return false, nil
+	}
+	return true, exec.Command(dst.Name(), "--version").Run()
+}
+
+// annotationNosuidNoexec is set by validation tests to the mount
+// destinations whose nosuid and noexec options should be checked.
+const annotationNosuidNoexec = "com.github.opencontainers.runtime-tools.runtimetest.nosuid-noexec"
+
+func (c *complianceTester) validateMountsNosuidNoexec(spec *rspec.Spec) error {
+	dests, ok := annotationDestinations(spec, annotationNosuidNoexec)
+	if !ok {
+		c.harness.Skip(1, "no nosuid or noexec mounts to check")
+		return nil
+	}
+	var found bool
+	for i, m := range spec.Mounts {
+		if !dests[filepath.Clean(m.Destination)] {
+			continue
+		}
+		var nosuid, noexec bool
+		for _, o := range m.Options {
+			switch o {
+			case "nosuid":
+				nosuid = true
+			case "suid":
+				nosuid = false
+			case "noexec":
+				noexec = true
+			case "exec":
+				noexec = false
+			}
+		}
+		if !nosuid && !noexec {
+			continue
+		}
+		found = true
+
+		var st unix.Statfs_t
+		if err := unix.Statfs(m.Destination, &st); err != nil {
+			return err
+		}
+
+		// The process usually runs as root, for which a setuid root binary
+		// changes nothing, so nosuid is checked on the mount flags.
+		if nosuid {
+			c.harness.Ok(int64(st.Flags)&unix.ST_NOSUID != 0, fmt.Sprintf("mounts[%d] (%s) ignores setuid bits", i, m.Destination))
+			_ = c.harness.YAML(map[string]interface{}{
+				"mount": m,
+			})
+		}
+
+		if noexec {
+			// Try running a binary from the mount, falling back to the mount
+			// flags where nothing can be written to it.
+			var ok bool
+			var actual string
+			created, err := execFrom(m.Destination)
+			if created {
+				ok = errors.Is(err, syscall.EACCES)
+				actual = "exec succeeded"
+				if err != nil {
+					actual = err.Error()
+				}
+			} else {
+				ok = int64(st.Flags)&unix.ST_NOEXEC != 0
+				actual = fmt.Sprintf("mount flags %#x", st.Flags)
+			}
+			c.harness.Ok(ok, fmt.Sprintf("mounts[%d] (%s) does not allow exec", i, m.Destination))
+			_ = c.harness.YAML(map[string]interface{}{
+				"mount":  m,
+				"actual": actual,
+			})
+		}
+	}
+	if !found {
+		c.harness.Skip(1, "no mounts with nosuid or noexec")
+	}
+	return nil
+}
+
+// mountFlagOptions maps the per-mount flags shown in mountinfo to the mount
+// option that clears each of them.
+var mountFlagOptions = map[string]string{
+	"ro":     "rw",
+	"nosuid": "suid",
+	"nodev":  "dev",
+	"noexec": "exec",
+}
+
+// annotationMountFlags is set by validation tests to the mount destinations
+// whose combined flags should all be checked in mountinfo.
+const annotationMountFlags = "com.github.opencontainers.runtime-tools.runtimetest.mount-flags"
+
+func (c *complianceTester) validateMountFlagsPreserved(spec *rspec.Spec) error {
+	dests, ok := annotationDestinations(spec, annotationMountFlags)
+	if !ok {
+		c.harness.Skip(1, "no mount flags to check")
+		return nil
+	}
+	mountInfos, err := mount.GetMounts()
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for i, m := range spec.Mounts {
+		if !dests[filepath.Clean(m.Destination)] {
+			continue
+		}
+		set := map[string]bool{}
+		for _, o := range m.Options {
+			if _, ok := mountFlagOptions[o]; ok {
+				set[o] = true
+			}
+			for flag, clear := range mountFlagOptions {
+				if o == clear {
+					delete(set, flag)
+				}
+			}
+		}
+		// Only mounts combining flags are checked here; single flags are
+		// covered by the ro, nosuid and noexec checks.
+		if len(set) < 2 {
+			continue
+		}
+		found = true
+
+		// The last entry for the destination is the one on top.
+		var info *mount.Info
+		for _, mi := range mountInfos {
+			if mi.Mountpoint == filepath.Clean(m.Destination) {
+				info = mi
+			}
+		}
+		if info == nil {
+			return fmt.Errorf("mounts[%d] (%s) not found in mountinfo", i, m.Destination)
+		}
+
+		actual := map[string]bool{}
+		for _, o := range strings.Split(info.Opts, ",") {
+			actual[o] = true
+		}
+		var expected, missing []string
+		for flag := range set {
+			expected = append(expected, flag)
+			if !actual[flag] {
+				missing = append(missing, flag)
+			}
+		}
+		sort.Strings(expected)
+		sort.Strings(missing)
+
+		c.harness.Ok(len(missing) == 0, fmt.Sprintf("mounts[%d] (%s) has all of %s", i, m.Destination, strings.Join(expected, ",")))
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": expected,
+			"actual":   info.Opts,
+			"missing":  missing,
+		})
+	}
+
+	if !found {
+		c.harness.Skip(1, "no mounts combining several flags")
+	}
+	return nil
+}
+
+func (c *complianceTester) validateMountsReadonly(spec *rspec.Spec) error {
+	var found bool
+	for i, m := range spec.Mounts {
+		var bind, ro bool
+		for _, o := range m.Options {
+			switch o {
+			case "bind", "rbind":
+				bind = true
+			case "ro":
+				ro = true
+			}
+		}
+		if !bind || !ro {
+			continue
+		}
+		found = true
+
+		fi, err := os.Stat(m.Destination)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			var f *os.File
+			f, err = os.CreateTemp(m.Destination, "runtimetest-ro")
+			if err == nil {
+				f.Close()
+				os.Remove(f.Name())
+			}
+		} else {
+			var f *os.File
+			f, err = os.OpenFile(m.Destination, os.O_WRONLY, 0)
+			if err == nil {
+				f.Close()
+			}
+		}
+
+		c.harness.Ok(errors.Is(err, syscall.EROFS), fmt.Sprintf("mounts[%d] (%s) is a read-only bind mount", i, m.Destination))
+		actual := "write succeeded"
+		if err != nil {
+			actual = err.Error()
+		}
+		_ = c.harness.YAML(map[string]interface{}{
+			"expected": syscall.EROFS.Error(),
+			"actual":   actual,
+		})
+	}
+
+	if !found {
+		c.harness.Skip(1, "no read-only bind mounts set")
+	}
+
+	return nil
+}
+
+func (c *complianceTester) validateCgroupsPath(spec *rspec.Spec) error {
+	if spec.Linux == nil || spec.Linux.CgroupsPath == "" {
+		c.harness.Skip(1, "linux.cgroupsPath not set")
+		return nil
+	}
+	for _, ns := range spec.Linux.Namespaces {
+		if ns.Type == rspec.CgroupNamespace {
+			c.harness.Skip(1, "cgroup paths are virtualized inside a cgroup namespace")
+			return nil
+		}
+	}
+
+	contents, err := os.ReadFile("/proc/self/cgroup")
+	if os.IsNotExist(err) {
+		c.harness.Skip(1, "/proc/self/cgroup does not exist")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// On cgroup v1 every controller hierarchy has its own line, while
+	// cgroup v2 has one "0::<path>" line for the unified hierarchy. Hybrid
+	// hosts list both, and then the v1 controllers are authoritative.
+	var v1, v2 []string
+	for _, line := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+		elem := strings.SplitN(line, ":", 3)
+		if len(elem) < 3 {
+			continue
+		}
+		if elem[1] == "" {
+			v2 = append(v2, elem[2])
+		} else {
+			v1 = append(v1, elem[2])
+		}
+	}
+	paths := v1
+	if len(paths) == 0 {
+		paths = v2
+	}
+	if len(paths) == 0 {
+		c.harness.Skip(1, "no cgroup controllers available")
+		return nil
+	}
+
+	cgroupsPath := spec.Linux.CgroupsPath
+	var mismatched []string
+	for _, path := range paths {
+		if filepath.IsAbs(cgroupsPath) && path != filepath.Clean(cgroupsPath) {
+			mismatched = append(mismatched, path)
+		} else if !filepath.IsAbs(cgroupsPath) && !strings.HasSuffix(path, "/"+filepath.Clean(cgroupsPath)) {
+			mismatched = append(mismatched, path)
+		}
+	}
+
+	rfcError, err := c.Ok(len(mismatched) == 0, specerror.CgroupsPathAttach, spec.Version, fmt.Sprintf("container is attached to the cgroups at %q", cgroupsPath))
+	if err != nil {
+		return err
+	}
+	if len(mismatched) > 0 {
+		_ = c.harness.YAML(map[string]interface{}{
+			"level":     rfcError.Level.String(),
+			"reference": rfcError.Reference,
+			"expected":  cgroupsPath,
+			"actual":    mismatched,
+		})
+	}
+
+	return nil
+}
+
+func run(context *cli.Context) error {
+	logLevelString := context.String("log-level")
+	logLevel, err := logrus.ParseLevel(logLevelString)
+	if err != nil {
+		return err
+	}
+	logrus.SetLevel(logLevel)
+
+	if context.IsSet("allocate") {
+		allocate(context.Int64("allocate"))
+		return nil
 	}
-	logrus.SetLevel(logLevel)
 
 	platform := runtime.GOOS
 	if platform != "linux" && platform != "solaris" && platform != "windows" {
@@ -1283,6 +2879,32 @@ func run(context *cli.Context) error {
 
 	c.harness.Header(0)
 
+	if context.Bool("exec") {
+		// The exec'd process follows its own process spec, not the one of
+		// the container process in config.json.
+		if path := context.String("exec-process"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var process rspec.Process
+			if err := json.Unmarshal(data, &process); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			spec.Process = &process
+		}
+		for _, validation := range []validator{
+			c.validateExecNamespaces,
+			c.validateCapabilities,
+		} {
+			if err := validation(spec); err != nil {
+				return err
+			}
+		}
+		c.harness.AutoPlan()
+		return nil
+	}
+
 	defaultValidations := []validator{
 		c.validateRootFS,
 		c.validateHostname,
@@ -1292,16 +2914,24 @@ func run(context *cli.Context) error {
 	posixValidations := []validator{
 		c.validatePosixMounts,
 		c.validatePosixUser,
+		c.validateUmask,
 		c.validateRlimits,
 	}
 
 	linuxValidations := []validator{
 		c.validateCapabilities,
+		c.validatePrivilegedMount,
 		c.validateDefaultSymlinks,
 		c.validateDefaultFS,
+		c.validateProcMount,
 		c.validateDefaultDevices,
 		c.validateLinuxDevices,
+		c.validateDeviceCgroup,
 		c.validateLinuxProcess,
+		c.validateTerminal,
+		c.validateConsoleSize,
+		c.validateEmptyEnv,
+		c.validateSignalMask,
 		c.validateMaskedPaths,
 		c.validateOOMScoreAdj,
 		c.validateSeccomp,
@@ -1310,8 +2940,24 @@ func run(context *cli.Context) error {
 		c.validateSysctls,
 		c.validateUIDMappings,
 		c.validateGIDMappings,
+		c.validateRootfsOwnership,
+		c.validateHostPIDNamespace,
 		c.validateMountLabel,
+		c.validateMountLabelFileContext,
 		c.validateApparmorProfile,
+		c.validateMountIDMappings,
+		c.validateMountsReadonly,
+		c.validateSysfsReadonly,
+		c.validateCgroupReadonly,
+		c.validateMountsNosuidNoexec,
+		c.validateMountFlagsPreserved,
+		c.validateTmpfsSize,
+		c.validateCgroupsPath,
+		c.validateNetworkNamespaceInterfaces,
+		c.validateMountNamespaceIsolation,
+		c.validateOrphansReaped,
+		c.validateMemoryLimit,
+		c.validateCPUAffinity,
 	}
 
 	validations := defaultValidations
@@ -1359,6 +3005,21 @@ func main() {
 			Value: "must",
 			Usage: "Compliance level (may, should or must)",
 		},
+		cli.Int64Flag{
+			Name:   "allocate",
+			Usage:  "Allocate this many bytes and exit, for the memory limit test",
+			Hidden: true,
+		},
+		cli.BoolFlag{
+			Name:   "exec",
+			Usage:  "Only check this process against the container process it was exec'd into, for the exec tests",
+			Hidden: true,
+		},
+		cli.StringFlag{
+			Name:   "exec-process",
+			Usage:  "Path to the process.json this process was exec'd with, for the exec tests",
+			Hidden: true,
+		},
 	}
 
 	app.Action = run
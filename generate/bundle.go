@@ -0,0 +1,158 @@
+package generate
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SaveBundle writes a runnable bundle into dir: config.json, plus the root
+// filesystem at g.Config.Root.Path (relative to dir) populated from
+// rootfsSource. rootfsSource is either a directory, which is copied, or a tar
+// archive, optionally gzip-compressed, which is extracted. A relative root
+// path is required, so the bundle can be moved as a whole.
+func (g *Generator) SaveBundle(dir string, rootfsSource string, opts ExportOptions) error {
+	if g.Config == nil || g.Config.Root == nil || g.Config.Root.Path == "" {
+		return fmt.Errorf("root.path must be set to save a bundle")
+	}
+	if filepath.IsAbs(g.Config.Root.Path) {
+		return fmt.Errorf("root.path %q must be relative to the bundle", g.Config.Root.Path)
+	}
+	info, err := os.Stat(rootfsSource)
+	if err != nil {
+		return fmt.Errorf("rootfs source: %w", err)
+	}
+
+	rootfs := filepath.Join(dir, g.Config.Root.Path)
+	if err := os.MkdirAll(rootfs, 0755); err != nil {
+		return err
+	}
+	if info.IsDir() {
+		err = copyTree(rootfsSource, rootfs)
+	} else {
+		err = extractTar(rootfsSource, rootfs)
+	}
+	if err != nil {
+		return fmt.Errorf("populating rootfs from %q: %w", rootfsSource, err)
+	}
+
+	return g.SaveToFile(filepath.Join(dir, "config.json"), opts)
+}
+
+// copyTree copies the regular files, directories and symlinks under src into
+// dst, keeping their permission bits.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			return os.MkdirAll(target, mode.Perm())
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case mode.IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return writeFile(target, f, mode.Perm())
+		default:
+			return fmt.Errorf("%s: unsupported file type %s", path, mode.Type())
+		}
+	})
+}
+
+// extractTar extracts the tar archive at path into dst, decompressing it
+// first if it is gzipped. Entries that would land outside dst are rejected.
+func extractTar(path, dst string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = bufio.NewReader(f)
+	if magic, err := r.(*bufio.Reader).Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) || filepath.IsAbs(name) {
+			return fmt.Errorf("tar entry %q escapes the rootfs", hdr.Name)
+		}
+		target := filepath.Join(dst, name)
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			link := filepath.Clean(filepath.FromSlash(hdr.Linkname))
+			if link == ".." || strings.HasPrefix(link, ".."+string(filepath.Separator)) || filepath.IsAbs(link) {
+				return fmt.Errorf("tar entry %q links outside the rootfs", hdr.Name)
+			}
+			if err := os.Link(filepath.Join(dst, link), target); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("tar entry %q has unsupported type %q", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,16 @@
+//go:build linux
+// +build linux
+
+package generate
+
+import "golang.org/x/sys/unix"
+
+// IsCgroup2UnifiedMode reports whether the host mounts the cgroup v2 unified
+// hierarchy on /sys/fs/cgroup.
+func IsCgroup2UnifiedMode() bool {
+	var st unix.Statfs_t
+	if err := unix.Statfs("/sys/fs/cgroup", &st); err != nil {
+		return false
+	}
+	return st.Type == unix.CGROUP2_SUPER_MAGIC
+}
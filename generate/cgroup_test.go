@@ -0,0 +1,31 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultLinuxMountsCgroup(t *testing.T) {
+	defer func(mode func() bool) { cgroup2UnifiedMode = mode }(cgroup2UnifiedMode)
+
+	for _, cgroup2 := range []bool{false, true} {
+		cgroup2UnifiedMode = func() bool { return cgroup2 }
+		g, err := New("linux")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cgroupMounts []string
+		for _, mnt := range g.Mounts() {
+			if mnt.Destination == "/sys/fs/cgroup" {
+				cgroupMounts = append(cgroupMounts, mnt.Type)
+			}
+		}
+		if cgroup2 {
+			assert.Equal(t, []string{"cgroup2"}, cgroupMounts)
+		} else {
+			assert.Empty(t, cgroupMounts)
+		}
+		assert.Equal(t, defaultLinuxMounts(cgroup2), g.Mounts())
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package generate
+
+// IsCgroup2UnifiedMode reports whether the host mounts the cgroup v2 unified
+// hierarchy on /sys/fs/cgroup. It is always false off Linux.
+func IsCgroup2UnifiedMode() bool {
+	return false
+}
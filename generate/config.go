@@ -179,6 +179,13 @@ func (g *Generator) initConfigWindowsResources() {
 	}
 }
 
+func (g *Generator) initConfigWindowsResourcesCPU() {
+	g.initConfigWindowsResources()
+	if g.Config.Windows.Resources.CPU == nil {
+		g.Config.Windows.Resources.CPU = &rspec.WindowsCPUResources{}
+	}
+}
+
 func (g *Generator) initConfigWindowsResourcesMemory() {
 	g.initConfigWindowsResources()
 	if g.Config.Windows.Resources.Memory == nil {
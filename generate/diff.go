@@ -0,0 +1,80 @@
+package generate
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// DiffFromDefault returns the fields of g.Config which differ from the
+// config New creates for the same platform, keyed by their JSON names.
+// Objects are compared field by field, so only the changed parts of nested
+// objects are returned. Arrays are returned whole when they differ, since
+// their order matters. A field which is set in the default config but not in
+// g.Config is returned with a nil value.
+func (g *Generator) DiffFromDefault() (map[string]interface{}, error) {
+	os := "linux"
+	if g.Config != nil {
+		switch {
+		case g.Config.Windows != nil:
+			os = "windows"
+		case g.Config.Solaris != nil:
+			os = "solaris"
+		}
+	}
+	def, err := New(os)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := toJSONObject(g.Config)
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := toJSONObject(def.Config)
+	if err != nil {
+		return nil, err
+	}
+	return diffObjects(defaults, current), nil
+}
+
+// toJSONObject converts v to the generic form encoding/json decodes objects
+// into, so that it can be compared regardless of Go types and omitempty.
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func diffObjects(from, to map[string]interface{}) map[string]interface{} {
+	diff := map[string]interface{}{}
+	for key, value := range to {
+		old, ok := from[key]
+		if !ok {
+			diff[key] = value
+			continue
+		}
+		oldObj, oldIsObj := old.(map[string]interface{})
+		newObj, newIsObj := value.(map[string]interface{})
+		if oldIsObj && newIsObj {
+			if d := diffObjects(oldObj, newObj); len(d) > 0 {
+				diff[key] = d
+			}
+			continue
+		}
+		if !reflect.DeepEqual(old, value) {
+			diff[key] = value
+		}
+	}
+	for key := range from {
+		if _, ok := to[key]; !ok {
+			diff[key] = nil
+		}
+	}
+	return diff
+}
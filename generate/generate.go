@@ -2,22 +2,123 @@
 package generate
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	osFilepath "github.com/opencontainers/runtime-tools/filepath"
 	"github.com/opencontainers/runtime-tools/generate/seccomp"
+	"github.com/opencontainers/runtime-tools/specerror"
 	capsCheck "github.com/opencontainers/runtime-tools/validate/capabilities"
 	"github.com/syndtr/gocapability/capability"
 )
 
+const (
+	// AnnotationEntrypoint records the entrypoint part of the process args
+	// set with SetProcessEntrypoint, as a JSON array.
+	AnnotationEntrypoint = "com.github.opencontainers.runtime-tools.entrypoint"
+	// AnnotationCmd records the cmd part of the process args set with
+	// SetProcessCmd, as a JSON array.
+	AnnotationCmd = "com.github.opencontainers.runtime-tools.cmd"
+	// AnnotationConsoleSocket records the host path of the socket the
+	// runtime should be given with --console-socket, as set with
+	// SetProcessTerminalConsoleSocket.
+	AnnotationConsoleSocket = "com.github.opencontainers.runtime-tools.console-socket"
+	// AnnotationStartTimeout records how many seconds tools should wait for
+	// the container to start, as a decimal integer set with
+	// SetStartTimeout. Runtimes may ignore it.
+	AnnotationStartTimeout = "com.github.opencontainers.runtime-tools.start-timeout"
+)
+
 var (
+	// FeatureCapabilities maps the features AddCapabilitiesForFeature
+	// accepts to the capabilities they need:
+	//
+	//	audit-write       CAP_AUDIT_WRITE
+	//	chown             CAP_CHOWN
+	//	ipc-lock          CAP_IPC_LOCK
+	//	kill              CAP_KILL
+	//	mknod             CAP_MKNOD
+	//	mount             CAP_SYS_ADMIN
+	//	net-admin         CAP_NET_ADMIN
+	//	net-bind-service  CAP_NET_BIND_SERVICE
+	//	net-raw           CAP_NET_RAW
+	//	ptrace            CAP_SYS_PTRACE
+	//	setuid            CAP_SETUID, CAP_SETGID
+	//	sys-module        CAP_SYS_MODULE
+	//	sys-nice          CAP_SYS_NICE
+	//	sys-resource      CAP_SYS_RESOURCE
+	//	sys-time          CAP_SYS_TIME
+	FeatureCapabilities = map[string][]string{
+		"audit-write":      {"CAP_AUDIT_WRITE"},
+		"chown":            {"CAP_CHOWN"},
+		"ipc-lock":         {"CAP_IPC_LOCK"},
+		"kill":             {"CAP_KILL"},
+		"mknod":            {"CAP_MKNOD"},
+		"mount":            {"CAP_SYS_ADMIN"},
+		"net-admin":        {"CAP_NET_ADMIN"},
+		"net-bind-service": {"CAP_NET_BIND_SERVICE"},
+		"net-raw":          {"CAP_NET_RAW"},
+		"ptrace":           {"CAP_SYS_PTRACE"},
+		"setuid":           {"CAP_SETUID", "CAP_SETGID"},
+		"sys-module":       {"CAP_SYS_MODULE"},
+		"sys-nice":         {"CAP_SYS_NICE"},
+		"sys-resource":     {"CAP_SYS_RESOURCE"},
+		"sys-time":         {"CAP_SYS_TIME"},
+	}
+
+	// NamespaceProfiles maps the profiles SetLinuxNamespacesProfile accepts
+	// to the namespaces they create:
+	//
+	//	default         pid, network, ipc, uts and mount, as New sets
+	//	full-isolation  every namespace, which needs UID and GID mappings
+	//	host-network    default without network
+	//	host-pid        default without pid
+	//	host-ipc        default without ipc
+	//	host            mount only
+	NamespaceProfiles = map[string][]rspec.LinuxNamespaceType{
+		"default":        {rspec.PIDNamespace, rspec.NetworkNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"full-isolation": {rspec.PIDNamespace, rspec.NetworkNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace, rspec.UserNamespace, rspec.CgroupNamespace},
+		"host-network":   {rspec.PIDNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"host-pid":       {rspec.NetworkNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"host-ipc":       {rspec.PIDNamespace, rspec.NetworkNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"host":           {rspec.MountNamespace},
+	}
+
 	// Namespaces include the names of supported namespaces.
 	Namespaces = []string{"network", "pid", "mount", "ipc", "uts", "user", "cgroup"}
 
+	// DefaultCapabilities is the conventional reduced capability list that
+	// most container runtimes grant by default. It is the list New uses for
+	// linux configs.
+	DefaultCapabilities = []string{
+		"CAP_CHOWN",
+		"CAP_DAC_OVERRIDE",
+		"CAP_FSETID",
+		"CAP_FOWNER",
+		"CAP_MKNOD",
+		"CAP_NET_RAW",
+		"CAP_SETGID",
+		"CAP_SETUID",
+		"CAP_SETFCAP",
+		"CAP_SETPCAP",
+		"CAP_NET_BIND_SERVICE",
+		"CAP_SYS_CHROOT",
+		"CAP_KILL",
+		"CAP_AUDIT_WRITE",
+	}
+
 	// we don't care about order...and this is way faster...
 	removeFunc = func(s []string, i int) []string {
 		s[i] = s[len(s)-1]
@@ -32,11 +133,26 @@ type Generator struct {
 	// This is used to keep a cache of the ENVs added to improve
 	// performance when adding a huge number of ENV variables
 	envMap map[string]int
+	// defaultHookTimeout is applied to hooks added without a timeout.
+	defaultHookTimeout *int
+	// maxAllowedCaps is the ceiling capability adds are checked against,
+	// when not nil.
+	maxAllowedCaps map[string]bool
+	// dropDisallowedCaps makes adds of capabilities above the ceiling a
+	// no-op instead of an error.
+	dropDisallowedCaps bool
 }
 
 // ExportOptions have toggles for exporting only certain parts of the specification
 type ExportOptions struct {
-	Seccomp bool // seccomp toggles if only seccomp should be exported
+	Seccomp          bool // seccomp toggles if only seccomp should be exported
+	SortCapabilities bool // SortCapabilities sorts and deduplicates each capability set before exporting
+	// Reproducible implies SortCapabilities and also sorts hugepage limits by
+	// page size, so configs built in a different order export to the same
+	// bytes. Maps such as annotations and sysctls are always exported with
+	// sorted keys. The default mounts from New depend on the host's cgroup
+	// mode, which SetupCgroupV2Mount pins for output shared across hosts.
+	Reproducible bool
 }
 
 // New creates a configuration Generator with the default
@@ -90,125 +206,13 @@ func New(os string) (generator Generator, err error) {
 
 	if os == "linux" {
 		config.Process.Capabilities = &rspec.LinuxCapabilities{
-			Bounding: []string{
-				"CAP_CHOWN",
-				"CAP_DAC_OVERRIDE",
-				"CAP_FSETID",
-				"CAP_FOWNER",
-				"CAP_MKNOD",
-				"CAP_NET_RAW",
-				"CAP_SETGID",
-				"CAP_SETUID",
-				"CAP_SETFCAP",
-				"CAP_SETPCAP",
-				"CAP_NET_BIND_SERVICE",
-				"CAP_SYS_CHROOT",
-				"CAP_KILL",
-				"CAP_AUDIT_WRITE",
-			},
-			Permitted: []string{
-				"CAP_CHOWN",
-				"CAP_DAC_OVERRIDE",
-				"CAP_FSETID",
-				"CAP_FOWNER",
-				"CAP_MKNOD",
-				"CAP_NET_RAW",
-				"CAP_SETGID",
-				"CAP_SETUID",
-				"CAP_SETFCAP",
-				"CAP_SETPCAP",
-				"CAP_NET_BIND_SERVICE",
-				"CAP_SYS_CHROOT",
-				"CAP_KILL",
-				"CAP_AUDIT_WRITE",
-			},
-			Inheritable: []string{
-				"CAP_CHOWN",
-				"CAP_DAC_OVERRIDE",
-				"CAP_FSETID",
-				"CAP_FOWNER",
-				"CAP_MKNOD",
-				"CAP_NET_RAW",
-				"CAP_SETGID",
-				"CAP_SETUID",
-				"CAP_SETFCAP",
-				"CAP_SETPCAP",
-				"CAP_NET_BIND_SERVICE",
-				"CAP_SYS_CHROOT",
-				"CAP_KILL",
-				"CAP_AUDIT_WRITE",
-			},
-			Effective: []string{
-				"CAP_CHOWN",
-				"CAP_DAC_OVERRIDE",
-				"CAP_FSETID",
-				"CAP_FOWNER",
-				"CAP_MKNOD",
-				"CAP_NET_RAW",
-				"CAP_SETGID",
-				"CAP_SETUID",
-				"CAP_SETFCAP",
-				"CAP_SETPCAP",
-				"CAP_NET_BIND_SERVICE",
-				"CAP_SYS_CHROOT",
-				"CAP_KILL",
-				"CAP_AUDIT_WRITE",
-			},
-			Ambient: []string{
-				"CAP_CHOWN",
-				"CAP_DAC_OVERRIDE",
-				"CAP_FSETID",
-				"CAP_FOWNER",
-				"CAP_MKNOD",
-				"CAP_NET_RAW",
-				"CAP_SETGID",
-				"CAP_SETUID",
-				"CAP_SETFCAP",
-				"CAP_SETPCAP",
-				"CAP_NET_BIND_SERVICE",
-				"CAP_SYS_CHROOT",
-				"CAP_KILL",
-				"CAP_AUDIT_WRITE",
-			},
-		}
-		config.Mounts = []rspec.Mount{
-			{
-				Destination: "/proc",
-				Type:        "proc",
-				Source:      "proc",
-				Options:     []string{"nosuid", "noexec", "nodev"},
-			},
-			{
-				Destination: "/dev",
-				Type:        "tmpfs",
-				Source:      "tmpfs",
-				Options:     []string{"nosuid", "strictatime", "mode=755", "size=65536k"},
-			},
-			{
-				Destination: "/dev/pts",
-				Type:        "devpts",
-				Source:      "devpts",
-				Options:     []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620", "gid=5"},
-			},
-			{
-				Destination: "/dev/shm",
-				Type:        "tmpfs",
-				Source:      "shm",
-				Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"},
-			},
-			{
-				Destination: "/dev/mqueue",
-				Type:        "mqueue",
-				Source:      "mqueue",
-				Options:     []string{"nosuid", "noexec", "nodev"},
-			},
-			{
-				Destination: "/sys",
-				Type:        "sysfs",
-				Source:      "sysfs",
-				Options:     []string{"nosuid", "noexec", "nodev", "ro"},
-			},
+			Bounding:    append([]string(nil), DefaultCapabilities...),
+			Permitted:   append([]string(nil), DefaultCapabilities...),
+			Inheritable: append([]string(nil), DefaultCapabilities...),
+			Effective:   append([]string(nil), DefaultCapabilities...),
+			Ambient:     append([]string(nil), DefaultCapabilities...),
 		}
+		config.Mounts = defaultLinuxMounts(cgroup2UnifiedMode())
 		config.Linux = &rspec.Linux{
 			Resources: &rspec.LinuxResources{
 				Devices: []rspec.LinuxDeviceCgroup{
@@ -262,6 +266,146 @@ func New(os string) (generator Generator, err error) {
 	return Generator{Config: &config, envMap: envCache}, nil
 }
 
+// NewWithVersion creates a configuration Generator with the default
+// configuration for the current OS, targeting the given runtime-spec
+// version. Fields the version does not know about are removed from the
+// template. An error is returned for unknown versions.
+func NewWithVersion(version string) (generator Generator, err error) {
+	switch version {
+	case "1.0.0", "1.0.1", "1.0.2", rspec.Version:
+	default:
+		return generator, fmt.Errorf("unsupported runtime-spec version %q", version)
+	}
+
+	generator, err = New(runtime.GOOS)
+	if err != nil {
+		return generator, err
+	}
+	setSpecVersion(generator.Config, version)
+	return generator, nil
+}
+
+// setSpecVersion sets config.Version, removing the fields version does not
+// know about.
+func setSpecVersion(config *rspec.Spec, version string) {
+	config.Version = version
+	if version != rspec.Version {
+		removeFieldsSince110(config)
+	}
+}
+
+// NewMinimal creates a configuration Generator with the smallest config a
+// POSIX runtime accepts: the spec version, root.path "rootfs" and a process
+// running "sh" as root in "/". Unlike New, it adds no mounts, namespaces,
+// devices, resources or any other linux defaults, for runtimes which reject
+// or do not need them.
+func NewMinimal() Generator {
+	return NewFromSpec(&rspec.Spec{
+		Version: rspec.Version,
+		Root: &rspec.Root{
+			Path: "rootfs",
+		},
+		Process: &rspec.Process{
+			Args: []string{"sh"},
+			Cwd:  "/",
+		},
+	})
+}
+
+// removeFieldsSince110 removes the fields introduced in runtime-spec 1.1.0
+// from config.
+func removeFieldsSince110(config *rspec.Spec) {
+	config.ZOS = nil
+	for i := range config.Mounts {
+		config.Mounts[i].UIDMappings = nil
+		config.Mounts[i].GIDMappings = nil
+	}
+	if config.Process != nil {
+		config.Process.Scheduler = nil
+		config.Process.IOPriority = nil
+	}
+	if config.Linux == nil {
+		return
+	}
+	config.Linux.Personality = nil
+	config.Linux.TimeOffsets = nil
+	if config.Linux.Seccomp != nil {
+		config.Linux.Seccomp.ListenerPath = ""
+		config.Linux.Seccomp.ListenerMetadata = ""
+	}
+	if r := config.Linux.Resources; r != nil {
+		if r.CPU != nil {
+			r.CPU.Burst = nil
+			r.CPU.Idle = nil
+		}
+		if r.Memory != nil {
+			r.Memory.CheckBeforeUpdate = nil
+		}
+	}
+}
+
+// cgroup2UnifiedMode is IsCgroup2UnifiedMode, replaced by tests to set up
+// the default mounts of either cgroup mode.
+var cgroup2UnifiedMode = IsCgroup2UnifiedMode
+
+// defaultLinuxMounts returns the mounts New sets up for linux. A cgroup2
+// mount is only included for a host running the unified hierarchy, as
+// reported by cgroup2UnifiedMode, since a cgroup v1 hierarchy cannot be
+// described by a single mount.
+func defaultLinuxMounts(cgroup2 bool) []rspec.Mount {
+	mounts := []rspec.Mount{
+		{
+			Destination: "/proc",
+			Type:        "proc",
+			Source:      "proc",
+			Options:     []string{"nosuid", "noexec", "nodev"},
+		},
+		{
+			Destination: "/dev",
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Options:     []string{"nosuid", "strictatime", "mode=755", "size=65536k"},
+		},
+		{
+			Destination: "/dev/pts",
+			Type:        "devpts",
+			Source:      "devpts",
+			Options:     []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620", "gid=5"},
+		},
+		{
+			Destination: "/dev/shm",
+			Type:        "tmpfs",
+			Source:      "shm",
+			Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"},
+		},
+		{
+			Destination: "/dev/mqueue",
+			Type:        "mqueue",
+			Source:      "mqueue",
+			Options:     []string{"nosuid", "noexec", "nodev"},
+		},
+		{
+			Destination: "/sys",
+			Type:        "sysfs",
+			Source:      "sysfs",
+			Options:     []string{"nosuid", "noexec", "nodev", "ro"},
+		},
+	}
+	if cgroup2 {
+		mounts = append(mounts, cgroup2Mount())
+	}
+	return mounts
+}
+
+func cgroup2Mount() rspec.Mount {
+	return rspec.Mount{
+		Destination: "/sys/fs/cgroup",
+		Type:        "cgroup2",
+		Source:      "cgroup2",
+		Options:     []string{"nosuid", "noexec", "nodev", "relatime", "ro"},
+	}
+}
+
 // NewFromSpec creates a configuration Generator from a given
 // configuration.
 func NewFromSpec(config *rspec.Spec) Generator {
@@ -329,12 +473,31 @@ func (g *Generator) SetSpec(config *rspec.Spec) {
 }
 
 // Spec gets the configuration from the Generator g.
+// The returned value is a live reference, so changes made through it are
+// visible to g.
 //
 // Deprecated: Replace with generator.Config.
 func (g *Generator) Spec() *rspec.Spec {
 	return g.Config
 }
 
+// CopyConfig returns a deep copy of g.Config, which callers can modify or
+// keep around to diff against later states without affecting g. The copy is
+// made through JSON, so empty fields that are omitted on export come back nil.
+// It is not named Config because that is already the name of the field.
+func (g *Generator) CopyConfig() (rspec.Spec, error) {
+	var config rspec.Spec
+	if g.Config == nil {
+		return config, nil
+	}
+	data, err := json.Marshal(g.Config)
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(data, &config)
+	return config, err
+}
+
 // Save writes the configuration into w.
 func (g *Generator) Save(w io.Writer, exportOpts ExportOptions) (err error) {
 	var data []byte
@@ -349,10 +512,24 @@ func (g *Generator) Save(w io.Writer, exportOpts ExportOptions) (err error) {
 		}
 	}
 
+	config := g.Config
+	if exportOpts.SortCapabilities || exportOpts.Reproducible {
+		// Sort a copy, so that exporting leaves g.Config as it was built.
+		copied, err := g.CopyConfig()
+		if err != nil {
+			return err
+		}
+		config = &copied
+		sortProcessCapabilities(config)
+		if exportOpts.Reproducible {
+			sortLinuxResourcesHugepageLimits(config)
+		}
+	}
+
 	if exportOpts.Seccomp {
-		data, err = json.MarshalIndent(g.Config.Linux.Seccomp, "", "\t")
+		data, err = json.MarshalIndent(config.Linux.Seccomp, "", "\t")
 	} else {
-		data, err = json.MarshalIndent(g.Config, "", "\t")
+		data, err = json.MarshalIndent(config, "", "\t")
 	}
 	if err != nil {
 		return err
@@ -366,6 +543,42 @@ func (g *Generator) Save(w io.Writer, exportOpts ExportOptions) (err error) {
 	return nil
 }
 
+// sortProcessCapabilities sorts each set in config.Process.Capabilities
+// alphabetically and drops duplicated entries, so the output does not depend
+// on the order capabilities were added in.
+func sortProcessCapabilities(config *rspec.Spec) {
+	if config.Process == nil || config.Process.Capabilities == nil {
+		return
+	}
+	caps := config.Process.Capabilities
+	for _, set := range []*[]string{&caps.Bounding, &caps.Effective, &caps.Inheritable, &caps.Permitted, &caps.Ambient} {
+		if len(*set) == 0 {
+			continue
+		}
+		sort.Strings(*set)
+		sorted := (*set)[:1]
+		for _, c := range (*set)[1:] {
+			if c != sorted[len(sorted)-1] {
+				sorted = append(sorted, c)
+			}
+		}
+		*set = sorted
+	}
+}
+
+// sortLinuxResourcesHugepageLimits sorts config.Linux.Resources.HugepageLimits
+// by page size. The entries are keyed by page size, so their order carries no
+// meaning.
+func sortLinuxResourcesHugepageLimits(config *rspec.Spec) {
+	if config.Linux == nil || config.Linux.Resources == nil {
+		return
+	}
+	limits := config.Linux.Resources.HugepageLimits
+	sort.SliceStable(limits, func(i, j int) bool {
+		return limits[i].Pagesize < limits[j].Pagesize
+	})
+}
+
 // SaveToFile writes the configuration into a file.
 func (g *Generator) SaveToFile(path string, exportOpts ExportOptions) error {
 	f, err := os.Create(path)
@@ -376,6 +589,33 @@ func (g *Generator) SaveToFile(path string, exportOpts ExportOptions) error {
 	return g.Save(f, exportOpts)
 }
 
+// ExecProcess returns a deep copy of g.Config.Process, to be run in an
+// existing container with `runtime exec --process`, or nil if no process is
+// set. Like CopyConfig, the copy is made through JSON.
+func (g *Generator) ExecProcess() *rspec.Process {
+	if g.Config == nil || g.Config.Process == nil {
+		return nil
+	}
+	// A Process holds no values JSON cannot encode, so this cannot fail.
+	data, _ := json.Marshal(g.Config.Process)
+	var process rspec.Process
+	_ = json.Unmarshal(data, &process)
+	return &process
+}
+
+// SaveProcess writes only g.Config.Process into a file, as the process.json
+// taken by `runtime exec --process`. Use ValidateProcess to check it first.
+func (g *Generator) SaveProcess(path string) error {
+	if g.Config == nil || g.Config.Process == nil {
+		return fmt.Errorf("process must be set to save it")
+	}
+	data, err := json.MarshalIndent(g.Config.Process, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
 // SetVersion sets g.Config.Version.
 func (g *Generator) SetVersion(version string) {
 	g.initConfig()
@@ -388,6 +628,12 @@ func (g *Generator) SetRootPath(path string) {
 	g.Config.Root.Path = path
 }
 
+// SetRoot sets g.Config.Root.Path and g.Config.Root.Readonly.
+func (g *Generator) SetRoot(path string, readonly bool) {
+	g.SetRootPath(path)
+	g.SetRootReadonly(readonly)
+}
+
 // SetRootReadonly sets g.Config.Root.Readonly.
 func (g *Generator) SetRootReadonly(b bool) {
 	g.initConfigRoot()
@@ -420,6 +666,40 @@ func (g *Generator) AddAnnotation(key, value string) {
 	g.Config.Annotations[key] = value
 }
 
+// AddAnnotationsFromFile adds the annotations listed in the file at path
+// into g.Config.Annotations, one "key=value" per line. As in env files,
+// leading whitespace, blank lines and lines starting with '#' are skipped,
+// and the value is kept as is. A later line for the same key wins. Nothing
+// is added if any line is invalid.
+func (g *Generator) AddAnnotationsFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	type annotation struct{ key, value string }
+	var annotations []annotation
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimLeftFunc(strings.TrimSuffix(line, "\r"), unicode.IsSpace)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("%s:%d: annotation %q is not of the form key=value", path, i+1, line)
+		}
+		if strings.IndexFunc(key, unicode.IsSpace) >= 0 {
+			return fmt.Errorf("%s:%d: annotation key %q has white spaces", path, i+1, key)
+		}
+		annotations = append(annotations, annotation{key, value})
+	}
+
+	for _, a := range annotations {
+		g.AddAnnotation(a.key, a.value)
+	}
+	return nil
+}
+
 // RemoveAnnotation remove an annotation from g.Config.Annotations.
 func (g *Generator) RemoveAnnotation(key string) {
 	if g.Config == nil || g.Config.Annotations == nil {
@@ -428,6 +708,34 @@ func (g *Generator) RemoveAnnotation(key string) {
 	delete(g.Config.Annotations, key)
 }
 
+// SetStartTimeout records seconds, which must be positive, in the
+// AnnotationStartTimeout annotation.
+func (g *Generator) SetStartTimeout(seconds int) error {
+	if seconds <= 0 {
+		return fmt.Errorf("start timeout must be greater than zero, got %d", seconds)
+	}
+	g.AddAnnotation(AnnotationStartTimeout, strconv.Itoa(seconds))
+	return nil
+}
+
+// StartTimeout returns the start timeout recorded by SetStartTimeout, or 0
+// if none is recorded or the annotation is not a positive integer.
+func (g *Generator) StartTimeout() int {
+	if g.Config == nil || g.Config.Annotations == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(g.Config.Annotations[AnnotationStartTimeout])
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return seconds
+}
+
+// ClearStartTimeout removes the start timeout recorded by SetStartTimeout.
+func (g *Generator) ClearStartTimeout() {
+	g.RemoveAnnotation(AnnotationStartTimeout)
+}
+
 // RemoveHostname removes g.Config.Hostname, setting it to an empty string.
 func (g *Generator) RemoveHostname() {
 	if g.Config == nil {
@@ -475,6 +783,11 @@ func (g *Generator) SetProcessCwd(cwd string) {
 }
 
 // SetProcessNoNewPrivileges sets g.Config.Process.NoNewPrivileges.
+//
+// noNewPrivileges does not clear ambient capabilities, but they are dropped
+// on executing a setuid program or one with file capabilities either way.
+// With noNewPrivileges set, such a program grants nothing in their place,
+// so Warnings reports ambient capabilities for a non-root user.
 func (g *Generator) SetProcessNoNewPrivileges(b bool) {
 	g.initConfigProcess()
 	g.Config.Process.NoNewPrivileges = b
@@ -486,16 +799,107 @@ func (g *Generator) SetProcessTerminal(b bool) {
 	g.Config.Process.Terminal = b
 }
 
+// SetProcessTerminalConsoleSocket sets g.Config.Process.Terminal and records
+// path, which must be absolute, in the AnnotationConsoleSocket annotation.
+func (g *Generator) SetProcessTerminalConsoleSocket(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("console socket path %q is not an absolute path", path)
+	}
+	g.SetProcessTerminal(true)
+	g.AddAnnotation(AnnotationConsoleSocket, path)
+	return nil
+}
+
+// ProcessTerminalConsoleSocket returns the console socket path recorded by
+// SetProcessTerminalConsoleSocket.
+func (g *Generator) ProcessTerminalConsoleSocket() string {
+	if g.Config == nil || g.Config.Annotations == nil {
+		return ""
+	}
+	return g.Config.Annotations[AnnotationConsoleSocket]
+}
+
+// ClearProcessTerminalConsoleSocket removes the console socket path recorded
+// by SetProcessTerminalConsoleSocket. g.Config.Process.Terminal is left as it
+// is.
+func (g *Generator) ClearProcessTerminalConsoleSocket() {
+	g.RemoveAnnotation(AnnotationConsoleSocket)
+}
+
 // SetProcessApparmorProfile sets g.Config.Process.ApparmorProfile.
 func (g *Generator) SetProcessApparmorProfile(prof string) {
 	g.initConfigProcess()
 	g.Config.Process.ApparmorProfile = prof
 }
 
-// SetProcessArgs sets g.Config.Process.Args.
-func (g *Generator) SetProcessArgs(args []string) {
+// SetProcessArgs sets g.Config.Process.Args. It returns an error, leaving
+// the args unchanged, if args is empty or its first entry is blank.
+func (g *Generator) SetProcessArgs(args []string) error {
+	if err := checkProcessArgs(args); err != nil {
+		return err
+	}
 	g.initConfigProcess()
 	g.Config.Process.Args = args
+	return nil
+}
+
+func checkProcessArgs(args []string) error {
+	if len(args) == 0 {
+		return specerror.NewError(specerror.ProcArgsOneEntryRequired, fmt.Errorf("process.args must have at least one entry"), rspec.Version)
+	}
+	if strings.TrimSpace(args[0]) == "" {
+		return specerror.NewError(specerror.ProcArgsOneEntryRequired, fmt.Errorf("process.args[0] must not be empty"), rspec.Version)
+	}
+	return nil
+}
+
+// SetProcessEntrypoint sets the entrypoint part of g.Config.Process.Args,
+// which is followed by the cmd set with SetProcessCmd. The entrypoint is
+// recorded in the AnnotationEntrypoint annotation.
+func (g *Generator) SetProcessEntrypoint(entrypoint []string) {
+	g.setProcessArgsAnnotation(AnnotationEntrypoint, entrypoint)
+	g.initConfigProcess()
+	g.Config.Process.Args = append(append([]string{}, entrypoint...), g.ProcessCmd()...)
+}
+
+// SetProcessCmd sets the cmd part of g.Config.Process.Args, which follows
+// the entrypoint set with SetProcessEntrypoint. The cmd is recorded in the
+// AnnotationCmd annotation.
+func (g *Generator) SetProcessCmd(cmd []string) {
+	g.setProcessArgsAnnotation(AnnotationCmd, cmd)
+	g.initConfigProcess()
+	g.Config.Process.Args = append(append([]string{}, g.ProcessEntrypoint()...), cmd...)
+}
+
+// ProcessEntrypoint returns the entrypoint recorded by SetProcessEntrypoint.
+func (g *Generator) ProcessEntrypoint() []string {
+	return g.processArgsAnnotation(AnnotationEntrypoint)
+}
+
+// ProcessCmd returns the cmd recorded by SetProcessCmd.
+func (g *Generator) ProcessCmd() []string {
+	return g.processArgsAnnotation(AnnotationCmd)
+}
+
+func (g *Generator) setProcessArgsAnnotation(key string, args []string) {
+	if len(args) == 0 {
+		g.RemoveAnnotation(key)
+		return
+	}
+	// Marshaling a slice of strings cannot fail.
+	data, _ := json.Marshal(args)
+	g.AddAnnotation(key, string(data))
+}
+
+func (g *Generator) processArgsAnnotation(key string) []string {
+	if g.Config == nil || g.Config.Annotations == nil {
+		return nil
+	}
+	var args []string
+	if err := json.Unmarshal([]byte(g.Config.Annotations[key]), &args); err != nil {
+		return nil
+	}
+	return args
 }
 
 // ClearProcessEnv clears g.Config.Process.Env.
@@ -519,6 +923,48 @@ func (g *Generator) AddProcessEnv(name, value string) {
 	g.addEnv(fmt.Sprintf("%s=%s", name, value), name)
 }
 
+// AddProcessEnvExpanded adds s, in name=value form, into
+// g.Config.Process.Env like AddProcessEnv, after expanding $VAR and ${VAR}
+// in the value against the env already in the config, not the host's. So
+// "PATH=$PATH:/opt/bin" extends the configured PATH. Unknown variables
+// expand to the empty string; use AddProcessEnvExpandedStrict to reject
+// them instead.
+func (g *Generator) AddProcessEnvExpanded(s string) error {
+	return g.addProcessEnvExpanded(s, false)
+}
+
+// AddProcessEnvExpandedStrict is like AddProcessEnvExpanded, but returns an
+// error if the value refers to a variable missing from the config.
+func (g *Generator) AddProcessEnvExpandedStrict(s string) error {
+	return g.addProcessEnvExpanded(s, true)
+}
+
+func (g *Generator) addProcessEnvExpanded(s string, strict bool) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("env %q must be in name=value form", s)
+	}
+
+	var missing []string
+	value = os.Expand(value, func(key string) string {
+		if g.Config != nil && g.Config.Process != nil {
+			for i := len(g.Config.Process.Env) - 1; i >= 0; i-- {
+				if env := g.Config.Process.Env[i]; strings.HasPrefix(env, key+"=") {
+					return env[len(key)+1:]
+				}
+			}
+		}
+		missing = append(missing, key)
+		return ""
+	})
+	if strict && len(missing) > 0 {
+		return fmt.Errorf("env %q refers to unset variables: %s", s, strings.Join(missing, ", "))
+	}
+
+	g.AddProcessEnv(name, value)
+	return nil
+}
+
 // AddMultipleProcessEnv adds multiple name=value into g.Config.Process.Env, or replaces
 // existing entries with the given name.
 func (g *Generator) AddMultipleProcessEnv(envs []string) {
@@ -563,6 +1009,12 @@ func (g *Generator) AddProcessRlimits(rType string, rHard uint64, rSoft uint64)
 	g.Config.Process.Rlimits = append(g.Config.Process.Rlimits, newRlimit)
 }
 
+// AddProcessRlimitBoth adds rlimit into g.Config.Process.Rlimits with the
+// same hard and soft value, like AddProcessRlimits(rType, value, value).
+func (g *Generator) AddProcessRlimitBoth(rType string, value uint64) {
+	g.AddProcessRlimits(rType, value, value)
+}
+
 // RemoveProcessRlimits removes a rlimit from g.Config.Process.Rlimits.
 func (g *Generator) RemoveProcessRlimits(rType string) {
 	if g.Config == nil || g.Config.Process == nil {
@@ -813,6 +1265,27 @@ func (g *Generator) SetLinuxResourcesCPUPeriod(period uint64) {
 	g.Config.Linux.Resources.CPU.Period = &period
 }
 
+// SetLinuxResourcesCPUBurst sets g.Config.Linux.Resources.CPU.Burst, the
+// CPU time in microseconds a cgroup may accumulate and use beyond its quota
+// (cpu.max.burst on cgroup v2). It fails if a quota is set and burst is
+// larger than it, as the kernel rejects that.
+func (g *Generator) SetLinuxResourcesCPUBurst(burst uint64) error {
+	g.InitConfigLinuxResourcesCPU()
+	if quota := g.Config.Linux.Resources.CPU.Quota; quota != nil && *quota > 0 && burst > uint64(*quota) {
+		return fmt.Errorf("cpu burst %d exceeds the cpu quota %d", burst, *quota)
+	}
+	g.Config.Linux.Resources.CPU.Burst = &burst
+	return nil
+}
+
+// ClearLinuxResourcesCPUBurst clears g.Config.Linux.Resources.CPU.Burst.
+func (g *Generator) ClearLinuxResourcesCPUBurst() {
+	if g.Config == nil || g.Config.Linux == nil || g.Config.Linux.Resources == nil || g.Config.Linux.Resources.CPU == nil {
+		return
+	}
+	g.Config.Linux.Resources.CPU.Burst = nil
+}
+
 // SetLinuxResourcesCPURealtimeRuntime sets g.Config.Linux.Resources.CPU.RealtimeRuntime.
 func (g *Generator) SetLinuxResourcesCPURealtimeRuntime(time int64) {
 	g.InitConfigLinuxResourcesCPU()
@@ -890,6 +1363,32 @@ func (g *Generator) DropLinuxResourcesUnified(key string) {
 	delete(g.Config.Linux.Resources.Unified, key)
 }
 
+// SetLinuxResourcesMemoryHigh sets memory.high in
+// g.Config.Linux.Resources.Unified to value, a size accepted by
+// ParseMemoryString or "max". It only takes effect on cgroup v2 hosts.
+func (g *Generator) SetLinuxResourcesMemoryHigh(value string) error {
+	return g.setLinuxResourcesUnifiedBytes("memory.high", value)
+}
+
+// SetLinuxResourcesMemoryLow sets memory.low in
+// g.Config.Linux.Resources.Unified to value, a size accepted by
+// ParseMemoryString or "max". It only takes effect on cgroup v2 hosts.
+func (g *Generator) SetLinuxResourcesMemoryLow(value string) error {
+	return g.setLinuxResourcesUnifiedBytes("memory.low", value)
+}
+
+func (g *Generator) setLinuxResourcesUnifiedBytes(key, value string) error {
+	if value != "max" {
+		n, err := ParseMemoryString(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		value = strconv.FormatInt(n, 10)
+	}
+	g.AddLinuxResourcesUnified(key, value)
+	return nil
+}
+
 // SetLinuxResourcesMemoryLimit sets g.Config.Linux.Resources.Memory.Limit.
 func (g *Generator) SetLinuxResourcesMemoryLimit(limit int64) {
 	g.initConfigLinuxResourcesMemory()
@@ -973,6 +1472,17 @@ func (g *Generator) SetLinuxResourcesPidsLimit(limit int64) {
 	g.Config.Linux.Resources.Pids.Limit = limit
 }
 
+// SetLinuxResourcesPidsLimitUnlimited removes g.Config.Linux.Resources.Pids,
+// so the runtime leaves the pids limit alone. This is not the same as a limit
+// of -1, which some runtimes write as "max" to the pids cgroup while others
+// ignore it.
+func (g *Generator) SetLinuxResourcesPidsLimitUnlimited() {
+	if g.Config == nil || g.Config.Linux == nil || g.Config.Linux.Resources == nil {
+		return
+	}
+	g.Config.Linux.Resources.Pids = nil
+}
+
 // ClearLinuxSysctl clears g.Config.Linux.Sysctl.
 func (g *Generator) ClearLinuxSysctl() {
 	if g.Config == nil || g.Config.Linux == nil {
@@ -987,6 +1497,49 @@ func (g *Generator) AddLinuxSysctl(key, value string) {
 	g.Config.Linux.Sysctl[key] = value
 }
 
+// AddLinuxSysctlFromFile adds the settings of the sysctl.conf-format file at
+// path into g.Config.Linux.Sysctl. Each line is a "key = value" pair, and
+// blank lines and comments starting with '#' or ';' are skipped. As with
+// sysctl(8), a key whose first separator is '/' has its '/' and '.' swapped,
+// so "net/ipv4/ip_forward" becomes "net.ipv4.ip_forward", and a leading
+// '-', which only tells sysctl(8) to ignore errors, is dropped.
+func (g *Generator) AddLinuxSysctlFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	sysctls := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimPrefix(strings.TrimSpace(key), "-")
+		if !ok || key == "" {
+			return fmt.Errorf("%s:%d: invalid entry %q", path, i+1, line)
+		}
+		if sep := strings.IndexAny(key, "./"); sep >= 0 && key[sep] == '/' {
+			key = strings.Map(func(r rune) rune {
+				switch r {
+				case '/':
+					return '.'
+				case '.':
+					return '/'
+				}
+				return r
+			}, key)
+		}
+		sysctls[key] = strings.TrimSpace(value)
+	}
+
+	for key, value := range sysctls {
+		g.AddLinuxSysctl(key, value)
+	}
+	return nil
+}
+
 // RemoveLinuxSysctl removes a sysctl config from g.Config.Linux.Sysctl.
 func (g *Generator) RemoveLinuxSysctl(key string) {
 	if g.Config == nil || g.Config.Linux == nil || g.Config.Linux.Sysctl == nil {
@@ -1015,6 +1568,15 @@ func (g *Generator) AddLinuxUIDMapping(hid, cid, size uint32) {
 	g.Config.Linux.UIDMappings = append(g.Config.Linux.UIDMappings, idMapping)
 }
 
+// RemoveLinuxUIDMapping removes the mappings starting at container ID cid
+// from g.Config.Linux.UIDMappings.
+func (g *Generator) RemoveLinuxUIDMapping(cid uint32) {
+	if g.Config == nil || g.Config.Linux == nil {
+		return
+	}
+	g.Config.Linux.UIDMappings = removeIDMapping(g.Config.Linux.UIDMappings, cid)
+}
+
 // ClearLinuxGIDMappings clear g.Config.Linux.GIDMappings.
 func (g *Generator) ClearLinuxGIDMappings() {
 	if g.Config == nil || g.Config.Linux == nil {
@@ -1035,6 +1597,121 @@ func (g *Generator) AddLinuxGIDMapping(hid, cid, size uint32) {
 	g.Config.Linux.GIDMappings = append(g.Config.Linux.GIDMappings, idMapping)
 }
 
+// RemoveLinuxGIDMapping removes the mappings starting at container ID cid
+// from g.Config.Linux.GIDMappings.
+func (g *Generator) RemoveLinuxGIDMapping(cid uint32) {
+	if g.Config == nil || g.Config.Linux == nil {
+		return
+	}
+	g.Config.Linux.GIDMappings = removeIDMapping(g.Config.Linux.GIDMappings, cid)
+}
+
+// removeIDMapping returns mappings without the entries starting at container
+// ID cid.
+func removeIDMapping(mappings []rspec.LinuxIDMapping, cid uint32) []rspec.LinuxIDMapping {
+	kept := mappings[:0]
+	for _, m := range mappings {
+		if m.ContainerID != cid {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// AddLinuxUIDMappingFromSubID adds the ranges allocated to username in
+// /etc/subuid into g.Config.Linux.UIDMappings, mapped from container uid 1.
+func (g *Generator) AddLinuxUIDMappingFromSubID(username string) error {
+	mappings, err := subIDMappings("/etc/subuid", username)
+	if err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		g.AddLinuxUIDMapping(m.HostID, m.ContainerID, m.Size)
+	}
+	return nil
+}
+
+// AddLinuxGIDMappingFromSubID adds the ranges allocated to username in
+// /etc/subgid into g.Config.Linux.GIDMappings, mapped from container gid 1.
+func (g *Generator) AddLinuxGIDMappingFromSubID(username string) error {
+	mappings, err := subIDMappings("/etc/subgid", username)
+	if err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		g.AddLinuxGIDMapping(m.HostID, m.ContainerID, m.Size)
+	}
+	return nil
+}
+
+// subIDMappings reads the ranges allocated to username from the subuid(5) or
+// subgid(5) file at path, where entries may name the user or give its uid.
+func subIDMappings(path, username string) ([]rspec.LinuxIDMapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	owners := []string{username}
+	if u, err := user.Lookup(username); err == nil {
+		owners = append(owners, u.Uid)
+	}
+	return parseSubID(f, path, owners...)
+}
+
+// parseSubID returns the ranges allocated to any of owners in a subuid(5) or
+// subgid(5) file read from r, in the order they are listed, mapped one after
+// the other from container id 1. name is only used in errors.
+func parseSubID(r io.Reader, name string, owners ...string) ([]rspec.LinuxIDMapping, error) {
+	var mappings []rspec.LinuxIDMapping
+	cid := uint64(1)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s: invalid entry %q", name, line)
+		}
+		owned := false
+		for _, owner := range owners {
+			if fields[0] == owner {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			continue
+		}
+		start, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid start in entry %q: %w", name, line, err)
+		}
+		count, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil || count == 0 {
+			return nil, fmt.Errorf("%s: invalid count in entry %q", name, line)
+		}
+		if start+count-1 > math.MaxUint32 {
+			return nil, fmt.Errorf("%s: entry %q ends beyond the largest id", name, line)
+		}
+		if cid+count-1 > math.MaxUint32 {
+			return nil, fmt.Errorf("%s: entry %q maps beyond the largest container id", name, line)
+		}
+		mappings = append(mappings, rspec.LinuxIDMapping{ContainerID: uint32(cid), HostID: uint32(start), Size: uint32(count)})
+		cid += count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, fmt.Errorf("%s: no subordinate ids allocated to %s", name, owners[0])
+	}
+	return mappings, nil
+}
+
 // SetLinuxRootPropagation sets g.Config.Linux.RootfsPropagation.
 func (g *Generator) SetLinuxRootPropagation(rp string) error {
 	switch rp {
@@ -1055,6 +1732,24 @@ func (g *Generator) SetLinuxRootPropagation(rp string) error {
 	return nil
 }
 
+// SetDefaultHookTimeout sets the timeout, in seconds, given to hooks added
+// afterwards without one. Hooks already in g.Config are left alone.
+func (g *Generator) SetDefaultHookTimeout(seconds int) error {
+	if seconds <= 0 {
+		return fmt.Errorf("hook timeout must be positive, got %d", seconds)
+	}
+	g.defaultHookTimeout = &seconds
+	return nil
+}
+
+func (g *Generator) withDefaultHookTimeout(hook rspec.Hook) rspec.Hook {
+	if hook.Timeout == nil && g.defaultHookTimeout != nil {
+		timeout := *g.defaultHookTimeout
+		hook.Timeout = &timeout
+	}
+	return hook
+}
+
 // ClearPreStartHooks clear g.Config.Hooks.Prestart.
 func (g *Generator) ClearPreStartHooks() {
 	if g.Config == nil || g.Config.Hooks == nil {
@@ -1066,7 +1761,7 @@ func (g *Generator) ClearPreStartHooks() {
 // AddPreStartHook add a prestart hook into g.Config.Hooks.Prestart.
 func (g *Generator) AddPreStartHook(preStartHook rspec.Hook) {
 	g.initConfigHooks()
-	g.Config.Hooks.Prestart = append(g.Config.Hooks.Prestart, preStartHook)
+	g.Config.Hooks.Prestart = append(g.Config.Hooks.Prestart, g.withDefaultHookTimeout(preStartHook))
 }
 
 // ClearPostStopHooks clear g.Config.Hooks.Poststop.
@@ -1080,7 +1775,7 @@ func (g *Generator) ClearPostStopHooks() {
 // AddPostStopHook adds a poststop hook into g.Config.Hooks.Poststop.
 func (g *Generator) AddPostStopHook(postStopHook rspec.Hook) {
 	g.initConfigHooks()
-	g.Config.Hooks.Poststop = append(g.Config.Hooks.Poststop, postStopHook)
+	g.Config.Hooks.Poststop = append(g.Config.Hooks.Poststop, g.withDefaultHookTimeout(postStopHook))
 }
 
 // ClearPostStartHooks clear g.Config.Hooks.Poststart.
@@ -1094,14 +1789,172 @@ func (g *Generator) ClearPostStartHooks() {
 // AddPostStartHook adds a poststart hook into g.Config.Hooks.Poststart.
 func (g *Generator) AddPostStartHook(postStartHook rspec.Hook) {
 	g.initConfigHooks()
-	g.Config.Hooks.Poststart = append(g.Config.Hooks.Poststart, postStartHook)
+	g.Config.Hooks.Poststart = append(g.Config.Hooks.Poststart, g.withDefaultHookTimeout(postStartHook))
 }
 
 // AddMount adds a mount into g.Config.Mounts.
-func (g *Generator) AddMount(mnt rspec.Mount) {
+// A spec-coded error is returned if the mount destination is not absolute.
+func (g *Generator) AddMount(mnt rspec.Mount) error {
+	if err := g.checkMountDestination(mnt); err != nil {
+		return err
+	}
+
 	g.initConfig()
 
 	g.Config.Mounts = append(g.Config.Mounts, mnt)
+	return nil
+}
+
+// checkMountDestination returns a spec-coded error if the destination of
+// mnt is not an absolute path.
+func (g *Generator) checkMountDestination(mnt rspec.Mount) error {
+	if !osFilepath.IsAbs(g.platform(), mnt.Destination) {
+		return specerror.NewError(specerror.MountsDestAbs, fmt.Errorf("mount destination %q is not an absolute path", mnt.Destination), rspec.Version)
+	}
+	return nil
+}
+
+// platform guesses the target OS of g.Config, which decides how paths in it
+// are interpreted.
+func (g *Generator) platform() string {
+	if g.Config != nil && g.Config.Windows != nil {
+		return "windows"
+	}
+	return "linux"
+}
+
+// AddMountWithIDMapping adds an idmapped mount into g.Config.Mounts.
+// Only bind mounts can be idmapped, so an error is returned for any other
+// kind of mount.
+func (g *Generator) AddMountWithIDMapping(mnt rspec.Mount, uidMappings, gidMappings []rspec.LinuxIDMapping) error {
+	if !isBindMount(mnt) {
+		return fmt.Errorf("mount %s: idmapped mounts are only supported for bind mounts", mnt.Destination)
+	}
+
+	mnt.UIDMappings = uidMappings
+	mnt.GIDMappings = gidMappings
+	return g.AddMount(mnt)
+}
+
+// AddMountFromDockerVolume adds a bind mount into g.Config.Mounts from a
+// Docker-style volume string SRC:DST[:OPTIONS], where OPTIONS is a comma
+// separated list of ro, rw and mount propagation modes. Named volumes are
+// rejected, since they need a volume driver to be resolved to a path.
+func (g *Generator) AddMountFromDockerVolume(s string) error {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("invalid volume %q: expected SRC:DST[:OPTIONS]", s)
+	}
+	src, dst := parts[0], parts[1]
+	if !filepath.IsAbs(src) {
+		return fmt.Errorf("invalid volume %q: %q looks like a named volume, which needs a volume driver", s, src)
+	}
+	if !filepath.IsAbs(dst) {
+		return fmt.Errorf("invalid volume %q: destination %q is not an absolute path", s, dst)
+	}
+
+	options := []string{"rbind"}
+	if len(parts) == 3 {
+		for _, opt := range strings.Split(parts[2], ",") {
+			switch opt {
+			case "ro", "rw",
+				"private", "rprivate", "shared", "rshared", "slave", "rslave":
+				options = append(options, opt)
+			default:
+				return fmt.Errorf("invalid volume %q: unsupported option %q", s, opt)
+			}
+		}
+	}
+
+	return g.AddMount(rspec.Mount{
+		Destination: dst,
+		Type:        "bind",
+		Source:      src,
+		Options:     options,
+	})
+}
+
+// pseudoFilesystems are the mount types whose source is only a label, by
+// convention the type itself.
+var pseudoFilesystems = map[string]bool{
+	"tmpfs":   true,
+	"proc":    true,
+	"sysfs":   true,
+	"devpts":  true,
+	"mqueue":  true,
+	"cgroup":  true,
+	"cgroup2": true,
+}
+
+// AddMountInferred adds a mount into g.Config.Mounts like AddMount, after
+// filling in its type from a simplified form:
+//
+//   - a mount with a bind or rbind option, or with an absolute path as its
+//     source, is a bind mount, recursive unless "bind" is given;
+//   - a mount without a type whose source names a pseudo filesystem, such as
+//     "tmpfs" or "proc", gets that type.
+//
+// Pseudo filesystems without a source get their type as source. An error is
+// returned for a bind mount without a source, a pseudo filesystem whose
+// source is a path, and any other type without a source to mount.
+func (g *Generator) AddMountInferred(mnt rspec.Mount) error {
+	if mnt.Type == "" {
+		switch {
+		case isBindMount(mnt) || filepath.IsAbs(mnt.Source):
+			mnt.Type = "bind"
+		case pseudoFilesystems[mnt.Source]:
+			mnt.Type = mnt.Source
+		default:
+			return fmt.Errorf("mount %s: cannot infer the type of source %q", mnt.Destination, mnt.Source)
+		}
+	}
+
+	switch {
+	case isBindMount(mnt):
+		if mnt.Source == "" {
+			return fmt.Errorf("mount %s: a bind mount needs a source", mnt.Destination)
+		}
+		if pseudoFilesystems[mnt.Type] {
+			return fmt.Errorf("mount %s: a bind mount cannot have type %s", mnt.Destination, mnt.Type)
+		}
+		mnt.Type = "bind"
+		hasBindOption := false
+		for _, opt := range mnt.Options {
+			if opt == "bind" || opt == "rbind" {
+				hasBindOption = true
+				break
+			}
+		}
+		if !hasBindOption {
+			mnt.Options = append([]string{"rbind"}, mnt.Options...)
+		}
+	case pseudoFilesystems[mnt.Type]:
+		if strings.Contains(mnt.Source, "/") {
+			return fmt.Errorf("mount %s: a %s mount does not take a path as source, got %q", mnt.Destination, mnt.Type, mnt.Source)
+		}
+		if mnt.Source == "" {
+			mnt.Source = mnt.Type
+		}
+	default:
+		if mnt.Source == "" {
+			return fmt.Errorf("mount %s: a %s mount needs a source", mnt.Destination, mnt.Type)
+		}
+	}
+
+	return g.AddMount(mnt)
+}
+
+// isBindMount reports whether mnt is a bind or rbind mount.
+func isBindMount(mnt rspec.Mount) bool {
+	if mnt.Type == "bind" || mnt.Type == "rbind" {
+		return true
+	}
+	for _, opt := range mnt.Options {
+		if opt == "bind" || opt == "rbind" {
+			return true
+		}
+	}
+	return false
 }
 
 // RemoveMount removes a mount point on the dest directory
@@ -1116,6 +1969,164 @@ func (g *Generator) RemoveMount(dest string) {
 	}
 }
 
+// SetDevMountOptions sets the options of the /dev mount in g.Config.Mounts,
+// adding a tmpfs /dev mount if there is none.
+func (g *Generator) SetDevMountOptions(options []string) error {
+	for _, opt := range options {
+		switch {
+		case strings.HasPrefix(opt, "mode="):
+			if _, err := strconv.ParseUint(strings.TrimPrefix(opt, "mode="), 8, 32); err != nil {
+				return fmt.Errorf("invalid /dev mount option %q: mode must be an octal number", opt)
+			}
+		case strings.HasPrefix(opt, "size="):
+			if !validTmpfsSize(strings.TrimPrefix(opt, "size=")) {
+				return fmt.Errorf("invalid /dev mount option %q: size must be a number with an optional k, m, g or %% suffix", opt)
+			}
+		}
+	}
+
+	g.initConfig()
+	for i, mnt := range g.Config.Mounts {
+		if mnt.Destination == "/dev" {
+			g.Config.Mounts[i].Options = options
+			return nil
+		}
+	}
+	g.Config.Mounts = append(g.Config.Mounts, rspec.Mount{
+		Destination: "/dev",
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+		Options:     options,
+	})
+	return nil
+}
+
+// SetDevShmSize sets the size option of the /dev/shm mount in
+// g.Config.Mounts, adding a tmpfs /dev/shm mount if there is none.
+func (g *Generator) SetDevShmSize(bytes uint64) error {
+	if bytes == 0 {
+		return fmt.Errorf("/dev/shm size must be positive")
+	}
+	size := fmt.Sprintf("size=%d", bytes)
+
+	g.initConfig()
+	for i, mnt := range g.Config.Mounts {
+		if mnt.Destination != "/dev/shm" {
+			continue
+		}
+		if mnt.Type != "tmpfs" {
+			return fmt.Errorf("/dev/shm is a %s mount, not tmpfs", mnt.Type)
+		}
+		var options []string
+		for _, opt := range mnt.Options {
+			if !strings.HasPrefix(opt, "size=") {
+				options = append(options, opt)
+			}
+		}
+		g.Config.Mounts[i].Options = append(options, size)
+		return nil
+	}
+	g.Config.Mounts = append(g.Config.Mounts, rspec.Mount{
+		Destination: "/dev/shm",
+		Type:        "tmpfs",
+		Source:      "shm",
+		Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", size},
+	})
+	return nil
+}
+
+// ParseMemoryString parses a memory size such as "512m" or "2Gi" into bytes.
+// The k, m and g suffixes and their ki, mi and gi spellings are all powers of
+// 1024, in either case. Fractions, signs and suffixes such as "mb", which
+// tools disagree on, are rejected.
+func ParseMemoryString(s string) (int64, error) {
+	lower := strings.ToLower(s)
+	num := strings.TrimRight(lower, "kmgi")
+	var shift uint
+	switch lower[len(num):] {
+	case "":
+	case "k", "ki":
+		shift = 10
+	case "m", "mi":
+		shift = 20
+	case "g", "gi":
+		shift = 30
+	default:
+		return 0, fmt.Errorf("invalid memory size %q: unknown unit", s)
+	}
+	if num == "" || strings.Trim(num, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid memory size %q: expected a whole number of bytes with an optional k, m or g suffix", s)
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("invalid memory size %q: out of range", s)
+	}
+	return n << shift, nil
+}
+
+// validTmpfsSize reports whether size is a valid value for the tmpfs size option.
+func validTmpfsSize(size string) bool {
+	if size == "" {
+		return false
+	}
+	switch size[len(size)-1] {
+	case 'k', 'K', 'm', 'M', 'g', 'G', '%':
+		size = size[:len(size)-1]
+	}
+	_, err := strconv.ParseUint(size, 10, 64)
+	return err == nil
+}
+
+// RemoveDefaultMount removes the mount on dest from g.Config.Mounts if dest
+// is one of the default linux mounts set up by New, leaving any other mount
+// on dest in place.
+func (g *Generator) RemoveDefaultMount(dest string) {
+	for _, mnt := range defaultLinuxMounts(cgroup2UnifiedMode()) {
+		if mnt.Destination == dest {
+			g.RemoveMount(dest)
+			return
+		}
+	}
+}
+
+// ResetDefaultMounts restores the default linux mounts set up by New,
+// replacing any mount on one of their destinations. The defaults are put
+// first, in their original order, followed by the other mounts.
+func (g *Generator) ResetDefaultMounts() {
+	g.initConfig()
+
+	mounts := defaultLinuxMounts(cgroup2UnifiedMode())
+	defaults := make(map[string]bool, len(mounts))
+	for _, mnt := range mounts {
+		defaults[mnt.Destination] = true
+	}
+	for _, mnt := range g.Config.Mounts {
+		if !defaults[mnt.Destination] {
+			mounts = append(mounts, mnt)
+		}
+	}
+	g.Config.Mounts = mounts
+}
+
+// SetupCgroupV2Mount replaces every cgroup mount in g.Config.Mounts, and any
+// mount beneath /sys/fs/cgroup, with a single cgroup2 mount of the unified
+// hierarchy on /sys/fs/cgroup.
+func (g *Generator) SetupCgroupV2Mount() {
+	g.initConfig()
+
+	var mounts []rspec.Mount
+	for _, mnt := range g.Config.Mounts {
+		if mnt.Type == "cgroup" || mnt.Type == "cgroup2" {
+			continue
+		}
+		if dest := filepath.Clean(mnt.Destination); dest == "/sys/fs/cgroup" || strings.HasPrefix(dest, "/sys/fs/cgroup/") {
+			continue
+		}
+		mounts = append(mounts, mnt)
+	}
+	g.Config.Mounts = append(mounts, cgroup2Mount())
+}
+
 // Mounts returns the list of mounts
 func (g *Generator) Mounts() []rspec.Mount {
 	g.initConfig()
@@ -1167,12 +2178,66 @@ func (g *Generator) ClearProcessCapabilities() {
 	g.Config.Process.Capabilities.Ambient = []string{}
 }
 
+// SetProcessCapabilitiesDefault sets every capability set, ambient included,
+// to DefaultCapabilities, as New does for linux configs.
+func (g *Generator) SetProcessCapabilitiesDefault() {
+	g.initConfigProcessCapabilities()
+	g.Config.Process.Capabilities.Bounding = append([]string(nil), DefaultCapabilities...)
+	g.Config.Process.Capabilities.Effective = append([]string(nil), DefaultCapabilities...)
+	g.Config.Process.Capabilities.Inheritable = append([]string(nil), DefaultCapabilities...)
+	g.Config.Process.Capabilities.Permitted = append([]string(nil), DefaultCapabilities...)
+	g.Config.Process.Capabilities.Ambient = append([]string(nil), DefaultCapabilities...)
+}
+
+// SetMaxAllowedCapabilities sets the capabilities the AddProcessCapability*
+// methods may add afterwards; adding any other capability fails, or is
+// skipped after SetDropDisallowedCapabilities(true). Capabilities already in
+// g.Config are left alone. A nil allowed removes the ceiling.
+func (g *Generator) SetMaxAllowedCapabilities(allowed []string) error {
+	if allowed == nil {
+		g.maxAllowedCaps = nil
+		return nil
+	}
+	caps := make(map[string]bool, len(allowed))
+	for _, c := range allowed {
+		cp := strings.ToUpper(c)
+		if err := capsCheck.CapValid(cp, g.HostSpecific); err != nil {
+			return err
+		}
+		caps[cp] = true
+	}
+	g.maxAllowedCaps = caps
+	return nil
+}
+
+// SetDropDisallowedCapabilities sets whether adding a capability outside the
+// set given to SetMaxAllowedCapabilities is silently skipped rather than
+// returning an error.
+func (g *Generator) SetDropDisallowedCapabilities(drop bool) {
+	g.dropDisallowedCaps = drop
+}
+
+// capabilityAllowed reports whether cp may be added under the ceiling set
+// with SetMaxAllowedCapabilities, and the error to return if not.
+func (g *Generator) capabilityAllowed(cp string) (bool, error) {
+	if g.maxAllowedCaps == nil || g.maxAllowedCaps[cp] {
+		return true, nil
+	}
+	if g.dropDisallowedCaps {
+		return false, nil
+	}
+	return false, fmt.Errorf("capability %s is not in the maximum allowed set", cp)
+}
+
 // AddProcessCapability adds a process capability into all 5 capability sets.
 func (g *Generator) AddProcessCapability(c string) error {
 	cp := strings.ToUpper(c)
 	if err := capsCheck.CapValid(cp, g.HostSpecific); err != nil {
 		return err
 	}
+	if allowed, err := g.capabilityAllowed(cp); !allowed {
+		return err
+	}
 
 	g.initConfigProcessCapabilities()
 
@@ -1230,12 +2295,30 @@ func (g *Generator) AddProcessCapability(c string) error {
 	return nil
 }
 
-// AddProcessCapabilityAmbient adds a process capability into g.Config.Process.Capabilities.Ambient.
+// AddProcessCapabilityAmbient adds a process capability into
+// g.Config.Process.Capabilities.Ambient, and into the permitted and
+// inheritable sets the kernel requires an ambient capability to be in. Use
+// AddProcessCapabilityAmbientOnly to leave the other sets alone.
 func (g *Generator) AddProcessCapabilityAmbient(c string) error {
+	if err := g.AddProcessCapabilityAmbientOnly(c); err != nil {
+		return err
+	}
+	if err := g.AddProcessCapabilityPermitted(c); err != nil {
+		return err
+	}
+	return g.AddProcessCapabilityInheritable(c)
+}
+
+// AddProcessCapabilityAmbientOnly adds a process capability into
+// g.Config.Process.Capabilities.Ambient only.
+func (g *Generator) AddProcessCapabilityAmbientOnly(c string) error {
 	cp := strings.ToUpper(c)
 	if err := capsCheck.CapValid(cp, g.HostSpecific); err != nil {
 		return err
 	}
+	if allowed, err := g.capabilityAllowed(cp); !allowed {
+		return err
+	}
 
 	g.initConfigProcessCapabilities()
 
@@ -1260,6 +2343,9 @@ func (g *Generator) AddProcessCapabilityBounding(c string) error {
 	if err := capsCheck.CapValid(cp, g.HostSpecific); err != nil {
 		return err
 	}
+	if allowed, err := g.capabilityAllowed(cp); !allowed {
+		return err
+	}
 
 	g.initConfigProcessCapabilities()
 
@@ -1283,6 +2369,9 @@ func (g *Generator) AddProcessCapabilityEffective(c string) error {
 	if err := capsCheck.CapValid(cp, g.HostSpecific); err != nil {
 		return err
 	}
+	if allowed, err := g.capabilityAllowed(cp); !allowed {
+		return err
+	}
 
 	g.initConfigProcessCapabilities()
 
@@ -1306,6 +2395,9 @@ func (g *Generator) AddProcessCapabilityInheritable(c string) error {
 	if err := capsCheck.CapValid(cp, g.HostSpecific); err != nil {
 		return err
 	}
+	if allowed, err := g.capabilityAllowed(cp); !allowed {
+		return err
+	}
 
 	g.initConfigProcessCapabilities()
 
@@ -1329,6 +2421,9 @@ func (g *Generator) AddProcessCapabilityPermitted(c string) error {
 	if err := capsCheck.CapValid(cp, g.HostSpecific); err != nil {
 		return err
 	}
+	if allowed, err := g.capabilityAllowed(cp); !allowed {
+		return err
+	}
 
 	g.initConfigProcessCapabilities()
 
@@ -1346,6 +2441,76 @@ func (g *Generator) AddProcessCapabilityPermitted(c string) error {
 	return nil
 }
 
+// AddCapabilitiesForFeature adds the capabilities feature needs, as listed
+// in FeatureCapabilities, into the bounding, effective and permitted sets of
+// g.Config.Process.Capabilities.
+func (g *Generator) AddCapabilitiesForFeature(feature string) error {
+	caps, ok := FeatureCapabilities[feature]
+	if !ok {
+		return fmt.Errorf("unknown capability feature %q", feature)
+	}
+	for _, c := range caps {
+		for _, add := range []func(string) error{
+			g.AddProcessCapabilityBounding,
+			g.AddProcessCapabilityEffective,
+			g.AddProcessCapabilityPermitted,
+		} {
+			if err := add(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AddProcessCapabilitiesFromProc adds the capabilities in each set of the
+// process pid, as listed in /proc/<pid>/status, into the matching set of
+// g.Config.Process.Capabilities. Capabilities unknown to this tool are
+// skipped.
+func (g *Generator) AddProcessCapabilitiesFromProc(pid int) error {
+	path := fmt.Sprintf("/proc/%d/status", pid)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("not permitted to read the capabilities of process %d from %s: %w", pid, path, err)
+		}
+		return fmt.Errorf("reading the capabilities of process %d: %w", pid, err)
+	}
+
+	adders := map[string]func(string) error{
+		"CapInh": g.AddProcessCapabilityInheritable,
+		"CapPrm": g.AddProcessCapabilityPermitted,
+		"CapEff": g.AddProcessCapabilityEffective,
+		"CapBnd": g.AddProcessCapabilityBounding,
+		"CapAmb": g.AddProcessCapabilityAmbientOnly,
+	}
+	found := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		add, known := adders[key]
+		if !ok || !known {
+			continue
+		}
+		mask, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return fmt.Errorf("%s: parsing %s: %w", path, key, err)
+		}
+		for _, cap := range capability.List() {
+			if mask&(1<<uint(cap)) == 0 {
+				continue
+			}
+			if err := add(fmt.Sprintf("CAP_%s", strings.ToUpper(cap.String()))); err != nil {
+				return err
+			}
+		}
+		found++
+	}
+	if found == 0 {
+		return fmt.Errorf("%s lists no capability sets", path)
+	}
+	return nil
+}
+
 // DropProcessCapability drops a process capability from all 5 capability sets.
 func (g *Generator) DropProcessCapability(c string) error {
 	if g.Config == nil || g.Config.Process == nil || g.Config.Process.Capabilities == nil {
@@ -1491,6 +2656,23 @@ func (g *Generator) ClearLinuxNamespaces() {
 	g.Config.Linux.Namespaces = []rspec.LinuxNamespace{}
 }
 
+// SetLinuxNamespacesProfile sets g.Config.Linux.Namespaces to the new
+// namespaces of profile, as listed in NamespaceProfiles. The namespaces
+// already set are dropped, including those joined by path.
+func (g *Generator) SetLinuxNamespacesProfile(profile string) error {
+	types, ok := NamespaceProfiles[profile]
+	if !ok {
+		return fmt.Errorf("unknown namespace profile %q", profile)
+	}
+
+	g.initConfigLinux()
+	g.Config.Linux.Namespaces = make([]rspec.LinuxNamespace, 0, len(types))
+	for _, t := range types {
+		g.Config.Linux.Namespaces = append(g.Config.Linux.Namespaces, rspec.LinuxNamespace{Type: t})
+	}
+	return nil
+}
+
 // AddOrReplaceLinuxNamespace adds or replaces a namespace inside
 // g.Config.Linux.Namespaces.
 func (g *Generator) AddOrReplaceLinuxNamespace(ns string, path string) error {
@@ -1598,12 +2780,72 @@ func (g *Generator) RemoveLinuxResourcesDevice(allow bool, devType string, major
 	}
 }
 
+// AllowDevicesFromNodes adds devices into g.Config.Linux.Devices and, for
+// each character or block device, an "rwm" allow rule for its type, major
+// and minor into g.Config.Linux.Resources.Devices, unless that rule already
+// exists. FIFOs are not subject to the device cgroup and get no rule.
+func (g *Generator) AllowDevicesFromNodes(devices []rspec.LinuxDevice) {
+	for _, device := range devices {
+		g.AddDevice(device)
+
+		var devType string
+		switch device.Type {
+		case "c", "u":
+			devType = "c"
+		case "b":
+			devType = "b"
+		default:
+			continue
+		}
+
+		g.initConfigLinuxResources()
+		found := false
+		for _, rule := range g.Config.Linux.Resources.Devices {
+			if rule.Allow && rule.Type == devType && rule.Access == "rwm" &&
+				rule.Major != nil && *rule.Major == device.Major &&
+				rule.Minor != nil && *rule.Minor == device.Minor {
+				found = true
+				break
+			}
+		}
+		if !found {
+			major, minor := device.Major, device.Minor
+			g.AddLinuxResourcesDevice(true, devType, &major, &minor, "rwm")
+		}
+	}
+}
+
 // SetSyscallAction adds rules for syscalls with the specified action
 func (g *Generator) SetSyscallAction(arguments seccomp.SyscallOpts) error {
 	g.initConfigLinuxSeccomp()
 	return seccomp.ParseSyscallFlag(arguments, g.Config.Linux.Seccomp)
 }
 
+// AddLinuxSeccompSyscall appends syscall to g.Config.Linux.Seccomp.Syscalls.
+// Its args filter on the syscall arguments, such as only allowing ioctl with
+// specific request codes; every arg must use one of the SCMP_CMP_* operators
+// on an argument index from 0 to 5.
+func (g *Generator) AddLinuxSeccompSyscall(syscall rspec.LinuxSyscall) error {
+	if len(syscall.Names) == 0 {
+		return fmt.Errorf("seccomp syscall rule must name at least one syscall")
+	}
+	switch syscall.Action {
+	case rspec.ActKill, rspec.ActKillProcess, rspec.ActKillThread, rspec.ActTrap,
+		rspec.ActErrno, rspec.ActTrace, rspec.ActAllow, rspec.ActLog, rspec.ActNotify:
+	default:
+		return fmt.Errorf("unrecognized seccomp action: %s", syscall.Action)
+	}
+	for _, arg := range syscall.Args {
+		if err := seccomp.CheckArg(arg); err != nil {
+			return err
+		}
+	}
+
+	g.initConfigLinuxSeccomp()
+	g.Config.Linux.Seccomp.Syscalls = append(g.Config.Linux.Seccomp.Syscalls, syscall)
+	return nil
+}
+
 // SetDefaultSeccompAction sets the default action for all syscalls not defined
 // and then removes any syscall rules with this action already specified.
 func (g *Generator) SetDefaultSeccompAction(action string) error {
@@ -1617,6 +2859,44 @@ func (g *Generator) SetDefaultSeccompActionForce(action string) error {
 	return seccomp.ParseDefaultActionForce(action, g.Config.Linux.Seccomp)
 }
 
+// SetLinuxSeccompDefault sets g.Config.Linux.Seccomp.DefaultAction.
+// SCMP_ACT_NOTIFY is rejected, since it requires a listener for every unmatched syscall.
+func (g *Generator) SetLinuxSeccompDefault(action rspec.LinuxSeccompAction) error {
+	switch action {
+	case rspec.ActKill, rspec.ActKillProcess, rspec.ActKillThread, rspec.ActTrap,
+		rspec.ActErrno, rspec.ActTrace, rspec.ActAllow, rspec.ActLog:
+	case rspec.ActNotify:
+		return fmt.Errorf("%s cannot be used as the default seccomp action", action)
+	default:
+		return fmt.Errorf("unrecognized seccomp action: %s", action)
+	}
+
+	g.initConfigLinuxSeccomp()
+	g.Config.Linux.Seccomp.DefaultAction = action
+	return nil
+}
+
+// SetLinuxSeccompFromProfile replaces g.Config.Linux.Seccomp with the
+// Docker-format seccomp profile at path. Rules are filtered for the native
+// architecture and the capabilities in g.Config.Process.Capabilities.Bounding.
+func (g *Generator) SetLinuxSeccompFromProfile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var caps []string
+	if g.Config != nil && g.Config.Process != nil && g.Config.Process.Capabilities != nil {
+		caps = g.Config.Process.Capabilities.Bounding
+	}
+	config, err := seccomp.ParseProfile(data, runtime.GOARCH, caps)
+	if err != nil {
+		return err
+	}
+	g.initConfigLinux()
+	g.Config.Linux.Seccomp = config
+	return nil
+}
+
 // SetDomainName sets g.Config.Domainname
 func (g *Generator) SetDomainName(domain string) {
 	g.initConfig()
@@ -1647,12 +2927,84 @@ func (g *Generator) AddLinuxMaskedPaths(path string) {
 	g.Config.Linux.MaskedPaths = append(g.Config.Linux.MaskedPaths, path)
 }
 
-// AddLinuxReadonlyPaths adds readonly paths into g.Config.Linux.MaskedPaths.
+// RemoveLinuxMaskedPaths removes path from g.Config.Linux.MaskedPaths.
+func (g *Generator) RemoveLinuxMaskedPaths(path string) {
+	if g.Config == nil || g.Config.Linux == nil {
+		return
+	}
+	g.Config.Linux.MaskedPaths = removePath(g.Config.Linux.MaskedPaths, path)
+}
+
+// ClearLinuxMaskedPaths clears g.Config.Linux.MaskedPaths.
+func (g *Generator) ClearLinuxMaskedPaths() {
+	if g.Config == nil || g.Config.Linux == nil {
+		return
+	}
+	g.Config.Linux.MaskedPaths = []string{}
+}
+
+// ProcMaskedPaths are the /proc entries MaskProcPaths masks, which expose
+// kernel memory, timers and scheduler or SCSI state of the host.
+var ProcMaskedPaths = []string{
+	"/proc/kcore",
+	"/proc/latency_stats",
+	"/proc/timer_list",
+	"/proc/sched_debug",
+	"/proc/scsi",
+}
+
+// MaskProcPaths adds the ProcMaskedPaths entries missing from
+// g.Config.Linux.MaskedPaths.
+func (g *Generator) MaskProcPaths() {
+	g.initConfigLinux()
+	for _, path := range ProcMaskedPaths {
+		found := false
+		for _, p := range g.Config.Linux.MaskedPaths {
+			if p == path {
+				found = true
+				break
+			}
+		}
+		if !found {
+			g.Config.Linux.MaskedPaths = append(g.Config.Linux.MaskedPaths, path)
+		}
+	}
+}
+
+// AddLinuxReadonlyPaths adds readonly paths into g.Config.Linux.ReadonlyPaths.
 func (g *Generator) AddLinuxReadonlyPaths(path string) {
 	g.initConfigLinux()
 	g.Config.Linux.ReadonlyPaths = append(g.Config.Linux.ReadonlyPaths, path)
 }
 
+// RemoveLinuxReadonlyPaths removes path from g.Config.Linux.ReadonlyPaths.
+func (g *Generator) RemoveLinuxReadonlyPaths(path string) {
+	if g.Config == nil || g.Config.Linux == nil {
+		return
+	}
+	g.Config.Linux.ReadonlyPaths = removePath(g.Config.Linux.ReadonlyPaths, path)
+}
+
+// ClearLinuxReadonlyPaths clears g.Config.Linux.ReadonlyPaths.
+func (g *Generator) ClearLinuxReadonlyPaths() {
+	if g.Config == nil || g.Config.Linux == nil {
+		return
+	}
+	g.Config.Linux.ReadonlyPaths = []string{}
+}
+
+// removePath returns paths without any entry equal to path, keeping the
+// order of the other entries.
+func removePath(paths []string, path string) []string {
+	result := paths[:0]
+	for _, p := range paths {
+		if p != path {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 func addOrReplaceBlockIOThrottleDevice(tmpList []rspec.LinuxThrottleDevice, major int64, minor int64, rate uint64) []rspec.LinuxThrottleDevice {
 	throttleDevices := tmpList
 	for i, throttleDevice := range throttleDevices {
@@ -1792,6 +3144,14 @@ func (g *Generator) SetVMImageFormat(format string) error {
 	return nil
 }
 
+// SetWindows sets g.Config.Windows, dropping g.Config.Linux, since a config
+// targets a single platform and Windows runtimes reject Linux settings.
+func (g *Generator) SetWindows(windows rspec.Windows) {
+	g.initConfig()
+	g.Config.Windows = &windows
+	g.Config.Linux = nil
+}
+
 // SetWindowsHypervUntilityVMPath sets g.Config.Windows.HyperV.UtilityVMPath.
 func (g *Generator) SetWindowsHypervUntilityVMPath(path string) {
 	g.initConfigWindowsHyperV()
@@ -1850,21 +3210,51 @@ func (g *Generator) SetWindowsNetworkNamespace(path string) {
 }
 
 // SetWindowsResourcesCPU sets g.Config.Windows.Resources.CPU.
+// Like SetWindows, it drops g.Config.Linux.
 func (g *Generator) SetWindowsResourcesCPU(cpu rspec.WindowsCPUResources) {
 	g.initConfigWindowsResources()
 	g.Config.Windows.Resources.CPU = &cpu
+	g.Config.Linux = nil
+}
+
+// SetWindowsResourcesCPUCount sets g.Config.Windows.Resources.CPU.Count.
+// Like SetWindows, it drops g.Config.Linux.
+func (g *Generator) SetWindowsResourcesCPUCount(count uint64) {
+	g.initConfigWindowsResourcesCPU()
+	g.Config.Windows.Resources.CPU.Count = &count
+	g.Config.Linux = nil
+}
+
+// SetWindowsResourcesCPUShares sets g.Config.Windows.Resources.CPU.Shares.
+// Like SetWindows, it drops g.Config.Linux.
+func (g *Generator) SetWindowsResourcesCPUShares(shares uint16) {
+	g.initConfigWindowsResourcesCPU()
+	g.Config.Windows.Resources.CPU.Shares = &shares
+	g.Config.Linux = nil
+}
+
+// SetWindowsResourcesCPUMaximum sets g.Config.Windows.Resources.CPU.Maximum.
+// Like SetWindows, it drops g.Config.Linux.
+func (g *Generator) SetWindowsResourcesCPUMaximum(maximum uint16) {
+	g.initConfigWindowsResourcesCPU()
+	g.Config.Windows.Resources.CPU.Maximum = &maximum
+	g.Config.Linux = nil
 }
 
 // SetWindowsResourcesMemoryLimit sets g.Config.Windows.Resources.Memory.Limit.
+// Like SetWindows, it drops g.Config.Linux.
 func (g *Generator) SetWindowsResourcesMemoryLimit(limit uint64) {
 	g.initConfigWindowsResourcesMemory()
 	g.Config.Windows.Resources.Memory.Limit = &limit
+	g.Config.Linux = nil
 }
 
 // SetWindowsResourcesStorage sets g.Config.Windows.Resources.Storage.
+// Like SetWindows, it drops g.Config.Linux.
 func (g *Generator) SetWindowsResourcesStorage(storage rspec.WindowsStorageResources) {
 	g.initConfigWindowsResources()
 	g.Config.Windows.Resources.Storage = &storage
+	g.Config.Linux = nil
 }
 
 // SetWindowsServicing sets g.Config.Windows.Servicing.
@@ -1,16 +1,23 @@
 package generate_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	rfc2119 "github.com/opencontainers/runtime-tools/error"
 	"github.com/opencontainers/runtime-tools/generate"
+	"github.com/opencontainers/runtime-tools/generate/seccomp"
 	"github.com/opencontainers/runtime-tools/specerror"
 	"github.com/opencontainers/runtime-tools/validate"
 	"github.com/stretchr/testify/assert"
+	"github.com/syndtr/gocapability/capability"
 )
 
 // Smoke test to ensure that _at the very least_ our default configuration
@@ -94,6 +101,249 @@ func TestRemoveMount(t *testing.T) {
 	}
 }
 
+func TestAddMountWithIDMapping(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mappings := []rspec.LinuxIDMapping{{ContainerID: 0, HostID: 1000, Size: 1}}
+
+	err = g.AddMountWithIDMapping(rspec.Mount{
+		Destination: "/mnt",
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+	}, mappings, mappings)
+	assert.Error(t, err)
+
+	err = g.AddMountWithIDMapping(rspec.Mount{
+		Destination: "/mnt",
+		Source:      "/src",
+		Options:     []string{"rbind"},
+	}, mappings, mappings)
+	assert.NoError(t, err)
+	mounts := g.Mounts()
+	assert.Equal(t, mappings, mounts[len(mounts)-1].UIDMappings)
+	assert.Equal(t, mappings, mounts[len(mounts)-1].GIDMappings)
+}
+
+func TestSetLinuxSeccompDefault(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Config.Linux.Seccomp = nil
+
+	assert.NoError(t, g.SetLinuxSeccompDefault(rspec.ActErrno))
+	assert.Equal(t, rspec.ActErrno, g.Config.Linux.Seccomp.DefaultAction)
+	assert.Error(t, g.SetLinuxSeccompDefault(rspec.ActNotify))
+	assert.Error(t, g.SetLinuxSeccompDefault("SCMP_ACT_UNKNOWN"))
+	assert.Equal(t, rspec.ActErrno, g.Config.Linux.Seccomp.DefaultAction)
+}
+
+func TestSetProcessCapabilitiesDefault(t *testing.T) {
+	expected := []string{
+		"CAP_CHOWN",
+		"CAP_DAC_OVERRIDE",
+		"CAP_FSETID",
+		"CAP_FOWNER",
+		"CAP_MKNOD",
+		"CAP_NET_RAW",
+		"CAP_SETGID",
+		"CAP_SETUID",
+		"CAP_SETFCAP",
+		"CAP_SETPCAP",
+		"CAP_NET_BIND_SERVICE",
+		"CAP_SYS_CHROOT",
+		"CAP_KILL",
+		"CAP_AUDIT_WRITE",
+	}
+	assert.Equal(t, expected, generate.DefaultCapabilities)
+
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ClearProcessCapabilities()
+	g.SetProcessCapabilitiesDefault()
+	caps := g.Config.Process.Capabilities
+	for _, set := range [][]string{caps.Bounding, caps.Effective, caps.Inheritable, caps.Permitted, caps.Ambient} {
+		assert.Equal(t, generate.DefaultCapabilities, set)
+	}
+}
+
+func TestSetDevMountOptions(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := len(g.Mounts())
+	options := []string{"nosuid", "mode=700", "size=128m"}
+	assert.NoError(t, g.SetDevMountOptions(options))
+	assert.Equal(t, size, len(g.Mounts()))
+	for _, mnt := range g.Mounts() {
+		if mnt.Destination == "/dev" {
+			assert.Equal(t, options, mnt.Options)
+		}
+	}
+
+	assert.Error(t, g.SetDevMountOptions([]string{"mode=999"}))
+	assert.Error(t, g.SetDevMountOptions([]string{"size=big"}))
+
+	g.ClearMounts()
+	assert.NoError(t, g.SetDevMountOptions(options))
+	assert.Equal(t, []rspec.Mount{{Destination: "/dev", Type: "tmpfs", Source: "tmpfs", Options: options}}, g.Mounts())
+}
+
+func TestSpecAndCopyConfig(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := g.CopyConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, g.Config.Mounts, config.Mounts)
+	assert.Equal(t, g.Config.Process.Args, config.Process.Args)
+
+	g.Spec().Hostname = "live"
+	assert.Equal(t, "live", g.Config.Hostname)
+
+	config.Hostname = "copy"
+	config.Process.Args[0] = "copy"
+	assert.Equal(t, "live", g.Config.Hostname)
+	assert.Equal(t, "sh", g.Config.Process.Args[0])
+}
+
+func TestProcessEntrypointAndCmd(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetProcessCmd([]string{"-c", "true"})
+	assert.Equal(t, []string{"-c", "true"}, g.Config.Process.Args)
+	g.SetProcessEntrypoint([]string{"/bin/sh"})
+	assert.Equal(t, []string{"/bin/sh", "-c", "true"}, g.Config.Process.Args)
+	g.SetProcessCmd([]string{"-c", "false"})
+	assert.Equal(t, []string{"/bin/sh", "-c", "false"}, g.Config.Process.Args)
+
+	// The split survives a round trip through the config.
+	g = generate.NewFromSpec(g.Config)
+	assert.Equal(t, []string{"/bin/sh"}, g.ProcessEntrypoint())
+	assert.Equal(t, []string{"-c", "false"}, g.ProcessCmd())
+
+	g.SetProcessEntrypoint(nil)
+	assert.Equal(t, []string{"-c", "false"}, g.Config.Process.Args)
+	assert.NotContains(t, g.Config.Annotations, generate.AnnotationEntrypoint)
+}
+
+func TestAddMountFromDockerVolume(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ClearMounts()
+
+	assert.NoError(t, g.AddMountFromDockerVolume("/host:/container:ro,rslave"))
+	assert.NoError(t, g.AddMountFromDockerVolume("/data:/data"))
+	assert.Equal(t, []rspec.Mount{
+		{Destination: "/container", Type: "bind", Source: "/host", Options: []string{"rbind", "ro", "rslave"}},
+		{Destination: "/data", Type: "bind", Source: "/data", Options: []string{"rbind"}},
+	}, g.Mounts())
+
+	for _, volume := range []string{"data:/data", "/host:container", "/host", "/host:/container:ro:extra", "/host:/container:nocopy"} {
+		assert.Error(t, g.AddMountFromDockerVolume(volume), volume)
+	}
+	assert.Len(t, g.Mounts(), 2)
+}
+
+const dockerSeccompProfile = `{
+	"defaultAction": "SCMP_ACT_ERRNO",
+	"defaultErrnoRet": 1,
+	"archMap": [
+		{"architecture": "SCMP_ARCH_X86_64", "subArchitectures": ["SCMP_ARCH_X86", "SCMP_ARCH_X32"]},
+		{"architecture": "SCMP_ARCH_AARCH64", "subArchitectures": ["SCMP_ARCH_ARM"]}
+	],
+	"syscalls": [
+		{"names": ["read", "write"], "action": "SCMP_ACT_ALLOW", "comment": "always allowed"},
+		{"names": ["arch_prctl"], "action": "SCMP_ACT_ALLOW", "includes": {"arches": ["amd64", "x32"]}},
+		{"names": ["mount"], "action": "SCMP_ACT_ALLOW", "includes": {"caps": ["CAP_SYS_ADMIN"]}},
+		{"names": ["uname"], "action": "SCMP_ACT_ALLOW", "excludes": {"caps": ["CAP_SYS_ADMIN"]}}
+	]
+}`
+
+func TestSeccompParseProfile(t *testing.T) {
+	config, err := seccomp.ParseProfile([]byte(dockerSeccompProfile), "amd64", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errnoRet := uint(1)
+	assert.Equal(t, &rspec.LinuxSeccomp{
+		DefaultAction:   rspec.ActErrno,
+		DefaultErrnoRet: &errnoRet,
+		Architectures:   []rspec.Arch{rspec.ArchX86_64, rspec.ArchX86, rspec.ArchX32},
+		Syscalls: []rspec.LinuxSyscall{
+			{Names: []string{"read", "write"}, Action: rspec.ActAllow},
+			{Names: []string{"arch_prctl"}, Action: rspec.ActAllow},
+			{Names: []string{"uname"}, Action: rspec.ActAllow},
+		},
+	}, config)
+
+	config, err = seccomp.ParseProfile([]byte(dockerSeccompProfile), "arm64", []string{"CAP_SYS_ADMIN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []rspec.Arch{rspec.ArchAARCH64, rspec.ArchARM}, config.Architectures)
+	assert.Equal(t, []rspec.LinuxSyscall{
+		{Names: []string{"read", "write"}, Action: rspec.ActAllow},
+		{Names: []string{"mount"}, Action: rspec.ActAllow},
+	}, config.Syscalls)
+
+	_, err = seccomp.ParseProfile([]byte("{"), "amd64", nil)
+	assert.Error(t, err)
+}
+
+func TestSetLinuxSeccompFromProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seccomp.json")
+	if err := os.WriteFile(path, []byte(dockerSeccompProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, g.SetLinuxSeccompFromProfile(path))
+	assert.Equal(t, rspec.ActErrno, g.Config.Linux.Seccomp.DefaultAction)
+	assert.Equal(t, []string{"read", "write"}, g.Config.Linux.Seccomp.Syscalls[0].Names)
+	assert.Error(t, g.SetLinuxSeccompFromProfile(filepath.Join(t.TempDir(), "missing.json")))
+}
+
+func TestRemoveDefaultMount(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := append([]rspec.Mount(nil), g.Mounts()...)
+
+	g.RemoveDefaultMount("/dev/shm")
+	g.RemoveDefaultMount("/not/a/default")
+	var expected []rspec.Mount
+	for _, mnt := range defaults {
+		if mnt.Destination != "/dev/shm" {
+			expected = append(expected, mnt)
+		}
+	}
+	assert.Equal(t, expected, g.Mounts())
+
+	extra := rspec.Mount{Destination: "/data", Type: "bind", Source: "/data", Options: []string{"rbind"}}
+	assert.NoError(t, g.AddMount(extra))
+	g.ResetDefaultMounts()
+	assert.Equal(t, append(defaults, extra), g.Mounts())
+}
+
 func TestEnvCaching(t *testing.T) {
 	// Start with empty ENV and add a few
 	g, err := generate.New("windows")
@@ -155,3 +405,932 @@ func TestMultipleEnvCaching(t *testing.T) {
 	g.AddMultipleProcessEnv([]string{})
 	assert.Equal(t, []string(nil), g.Config.Process.Env)
 }
+
+func TestClearProcessEnv(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ClearProcessEnv()
+	g.AddProcessEnv("k1", "v1")
+	g.AddMultipleProcessEnv([]string{"k2=v2", "k1=v3"})
+
+	var buf bytes.Buffer
+	if err := g.Save(&buf, generate.ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var config rspec.Spec
+	if err := json.Unmarshal(buf.Bytes(), &config); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"k1=v3", "k2=v2"}, config.Process.Env)
+}
+
+func TestAddMountRelativeDestination(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := len(g.Mounts())
+
+	err = g.AddMount(rspec.Mount{Destination: "relative", Type: "tmpfs", Source: "tmpfs"})
+	assert.Equal(t, specerror.MountsDestAbs, err.(*specerror.Error).Code)
+	assert.Len(t, g.Mounts(), size)
+	assert.Empty(t, g.Validate())
+
+	g.Config.Mounts = append(g.Config.Mounts, rspec.Mount{Destination: "relative"})
+	errs := g.Validate()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, specerror.MountsDestAbs, errs[0].(*specerror.Error).Code)
+		assert.Contains(t, errs[0].Error(), "relative")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, os := range []string{"linux", "windows", "freebsd"} {
+		g, err := generate.New(os)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, g.Validate(), os)
+	}
+
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Config.Process.Args = nil
+	g.SetProcessCwd("relative")
+	g.Config.Process.Capabilities.Bounding = append(g.Config.Process.Capabilities.Bounding, "CAP_NOT_A_CAP")
+	g.AddProcessRlimits("RLIMIT_NOFILE", 2048, 2048)
+	g.Config.Process.Rlimits = append(g.Config.Process.Rlimits, g.Config.Process.Rlimits[0])
+	g.Config.Linux.Namespaces = append(g.Config.Linux.Namespaces, rspec.LinuxNamespace{Type: "bogus"})
+	g.SetLinuxResourcesMemoryLimit(1024)
+	g.SetLinuxResourcesMemoryReservation(2048)
+
+	var codes []specerror.Code
+	for _, err := range g.Validate() {
+		codes = append(codes, err.(*specerror.Error).Code)
+	}
+	assert.Equal(t, []specerror.Code{
+		specerror.ProcArgsOneEntryRequired,
+		specerror.ProcCwdAbs,
+		specerror.LinuxProcCapError,
+		specerror.PosixProcRlimitsErrorOnDup,
+		specerror.ValidValues,
+	}, codes)
+	assert.Equal(t, []string{"memory reservation 2048 is above the memory limit 1024"}, g.Warnings())
+}
+
+func TestRemoveLinuxMaskedAndReadonlyPaths(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/proc/acpi", "/proc/kcore", "/proc/keys"} {
+		g.AddLinuxMaskedPaths(path)
+		g.AddLinuxReadonlyPaths(path)
+	}
+
+	g.RemoveLinuxMaskedPaths("/proc/kcore")
+	g.RemoveLinuxReadonlyPaths("/proc/kcore")
+	assert.Equal(t, []string{"/proc/acpi", "/proc/keys"}, g.Config.Linux.MaskedPaths)
+	assert.Equal(t, []string{"/proc/acpi", "/proc/keys"}, g.Config.Linux.ReadonlyPaths)
+
+	g.ClearLinuxMaskedPaths()
+	g.ClearLinuxReadonlyPaths()
+	assert.Empty(t, g.Config.Linux.MaskedPaths)
+	assert.Empty(t, g.Config.Linux.ReadonlyPaths)
+}
+
+func TestNewWithVersion(t *testing.T) {
+	g, err := generate.NewWithVersion("1.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "1.0.2", g.Config.Version)
+
+	g, err = generate.NewWithVersion(rspec.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rspec.Version, g.Config.Version)
+
+	_, err = generate.NewWithVersion("0.5.0")
+	assert.Error(t, err)
+}
+
+func TestWindowsResources(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetWindowsResourcesCPUCount(2)
+	g.SetWindowsResourcesCPUShares(500)
+	g.SetWindowsResourcesMemoryLimit(1 << 30)
+	assert.Nil(t, g.Config.Linux)
+	assert.Equal(t, uint64(2), *g.Config.Windows.Resources.CPU.Count)
+	assert.Equal(t, uint16(500), *g.Config.Windows.Resources.CPU.Shares)
+	assert.Equal(t, uint64(1<<30), *g.Config.Windows.Resources.Memory.Limit)
+
+	g.SetWindows(rspec.Windows{LayerFolders: []string{`C:\layer`}})
+	assert.Equal(t, []string{`C:\layer`}, g.Config.Windows.LayerFolders)
+	assert.Nil(t, g.Config.Windows.Resources)
+	assert.Nil(t, g.Config.Linux)
+}
+
+func TestAddProcessCapabilityPerSet(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ClearProcessCapabilities()
+	if err := g.AddProcessCapabilityBounding("cap_net_admin"); err != nil {
+		t.Fatal(err)
+	}
+	caps := g.Config.Process.Capabilities
+	assert.Equal(t, []string{"CAP_NET_ADMIN"}, caps.Bounding)
+	assert.Empty(t, caps.Effective)
+	assert.Empty(t, caps.Inheritable)
+	assert.Empty(t, caps.Permitted)
+	assert.Empty(t, caps.Ambient)
+
+	assert.Error(t, g.AddProcessCapabilityPermitted("CAP_NOT_A_CAP"))
+	assert.Empty(t, caps.Permitted)
+}
+
+func TestSetupCgroupV2Mount(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cgroup v1 layout, with a tmpfs and one mount per controller.
+	g.ClearMounts()
+	for _, mnt := range []rspec.Mount{
+		{Destination: "/proc", Type: "proc", Source: "proc"},
+		{Destination: "/sys/fs/cgroup", Type: "tmpfs", Source: "tmpfs"},
+		{Destination: "/sys/fs/cgroup/memory", Type: "cgroup", Source: "cgroup", Options: []string{"memory"}},
+		{Destination: "/sys/fs/cgroup/cpu", Type: "cgroup", Source: "cgroup", Options: []string{"cpu"}},
+	} {
+		if err := g.AddMount(mnt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.SetupCgroupV2Mount()
+	mounts := g.Mounts()
+	if assert.Len(t, mounts, 2) {
+		assert.Equal(t, "/proc", mounts[0].Destination)
+		assert.Equal(t, "/sys/fs/cgroup", mounts[1].Destination)
+		assert.Equal(t, "cgroup2", mounts[1].Type)
+	}
+}
+
+func TestSaveSortCapabilities(t *testing.T) {
+	var outputs []string
+	for _, order := range [][]string{
+		{"CAP_SYS_ADMIN", "CAP_NET_ADMIN", "CAP_CHOWN"},
+		{"CAP_CHOWN", "CAP_SYS_ADMIN", "CAP_NET_ADMIN"},
+	} {
+		g, err := generate.New("linux")
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.ClearProcessCapabilities()
+		for _, c := range order {
+			if err := g.AddProcessCapability(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+		g.Config.Process.Capabilities.Bounding = append(g.Config.Process.Capabilities.Bounding, order[0])
+
+		var buf bytes.Buffer
+		if err := g.Save(&buf, generate.ExportOptions{SortCapabilities: true}); err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, buf.String())
+	}
+	assert.Equal(t, outputs[0], outputs[1])
+}
+
+func TestSetRoot(t *testing.T) {
+	combined, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	combined.SetRoot("/var/lib/rootfs", true)
+
+	separate, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	separate.SetRootPath("/var/lib/rootfs")
+	separate.SetRootReadonly(true)
+
+	assert.Equal(t, separate.Config.Root, combined.Config.Root)
+}
+
+func TestProcessTerminalConsoleSocket(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, g.SetProcessTerminalConsoleSocket("console.sock"))
+	assert.False(t, g.Config.Process.Terminal)
+
+	assert.NoError(t, g.SetProcessTerminalConsoleSocket("/run/console.sock"))
+	assert.True(t, g.Config.Process.Terminal)
+	assert.Equal(t, "/run/console.sock", g.ProcessTerminalConsoleSocket())
+
+	g.ClearProcessTerminalConsoleSocket()
+	assert.Equal(t, "", g.ProcessTerminalConsoleSocket())
+	assert.True(t, g.Config.Process.Terminal)
+}
+
+func TestAllowDevicesFromNodes(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fuse := rspec.LinuxDevice{Path: "/dev/fuse", Type: "c", Major: 10, Minor: 229}
+	g.AllowDevicesFromNodes([]rspec.LinuxDevice{fuse})
+	g.AllowDevicesFromNodes([]rspec.LinuxDevice{fuse})
+
+	assert.Equal(t, []rspec.LinuxDevice{fuse}, g.Config.Linux.Devices)
+	var rules []rspec.LinuxDeviceCgroup
+	for _, rule := range g.Config.Linux.Resources.Devices {
+		if rule.Allow {
+			rules = append(rules, rule)
+		}
+	}
+	if assert.Len(t, rules, 1) {
+		assert.Equal(t, "c", rules[0].Type)
+		assert.Equal(t, int64(10), *rules[0].Major)
+		assert.Equal(t, int64(229), *rules[0].Minor)
+		assert.Equal(t, "rwm", rules[0].Access)
+	}
+}
+
+func TestSetDefaultHookTimeout(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, g.SetDefaultHookTimeout(0))
+
+	g.AddPreStartHook(rspec.Hook{Path: "/bin/before"})
+	assert.NoError(t, g.SetDefaultHookTimeout(5))
+	explicit := 20
+	g.AddPreStartHook(rspec.Hook{Path: "/bin/explicit", Timeout: &explicit})
+	g.AddPostStartHook(rspec.Hook{Path: "/bin/after"})
+
+	assert.Nil(t, g.Config.Hooks.Prestart[0].Timeout)
+	assert.Equal(t, 20, *g.Config.Hooks.Prestart[1].Timeout)
+	assert.Equal(t, 5, *g.Config.Hooks.Poststart[0].Timeout)
+}
+
+func TestDiffFromDefault(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := g.DiffFromDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, diff)
+
+	g.SetHostname("example")
+	g.SetProcessArgs([]string{"sleep", "10"})
+	g.SetLinuxResourcesPidsLimit(64)
+	g.Config.Linux.Namespaces = nil
+	diff, err = g.DiffFromDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"hostname": "example",
+		"process": map[string]interface{}{
+			"args": []interface{}{"sleep", "10"},
+		},
+		"linux": map[string]interface{}{
+			"namespaces": nil,
+			"resources": map[string]interface{}{
+				"pids": map[string]interface{}{"limit": float64(64)},
+			},
+		},
+	}, diff)
+}
+
+func TestSetDevShmSize(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, g.SetDevShmSize(0))
+	assert.NoError(t, g.SetDevShmSize(1<<30))
+
+	var shm []rspec.Mount
+	for _, mnt := range g.Mounts() {
+		if mnt.Destination == "/dev/shm" {
+			shm = append(shm, mnt)
+		}
+	}
+	if assert.Len(t, shm, 1) {
+		assert.Equal(t, []string{"nosuid", "noexec", "nodev", "mode=1777", "size=1073741824"}, shm[0].Options)
+	}
+
+	g.RemoveMount("/dev/shm")
+	assert.NoError(t, g.SetDevShmSize(4096))
+	mounts := g.Mounts()
+	assert.Equal(t, "size=4096", mounts[len(mounts)-1].Options[4])
+}
+
+func TestAddCapabilitiesForFeature(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ClearProcessCapabilities()
+	assert.Error(t, g.AddCapabilitiesForFeature("time-travel"))
+	for _, feature := range []string{"net-admin", "sys-time", "mknod", "setuid"} {
+		assert.NoError(t, g.AddCapabilitiesForFeature(feature))
+	}
+
+	expected := []string{"CAP_NET_ADMIN", "CAP_SYS_TIME", "CAP_MKNOD", "CAP_SETUID", "CAP_SETGID"}
+	caps := g.Config.Process.Capabilities
+	assert.Equal(t, expected, caps.Bounding)
+	assert.Equal(t, expected, caps.Effective)
+	assert.Equal(t, expected, caps.Permitted)
+	assert.Empty(t, caps.Inheritable)
+	assert.Empty(t, caps.Ambient)
+}
+
+func TestWarningsNoNewPrivilegesAmbient(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetProcessNoNewPrivileges(true)
+	if err := g.AddProcessCapabilityAmbient("CAP_NET_BIND_SERVICE"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, g.Warnings())
+
+	g.SetProcessUID(1000)
+	assert.Empty(t, g.Validate())
+	assert.Equal(t, []string{"process.capabilities.ambient is ineffective for non-root uid 1000 with process.noNewPrivileges"}, g.Warnings())
+}
+
+func TestParseMemoryString(t *testing.T) {
+	for s, expected := range map[string]int64{
+		"1024": 1024,
+		"512m": 512 << 20,
+		"512M": 512 << 20,
+		"2g":   2 << 30,
+		"2Gi":  2 << 30,
+		"64ki": 64 << 10,
+	} {
+		n, err := generate.ParseMemoryString(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, n, s)
+	}
+	for _, s := range []string{"", "m", "1.5g", "-1", "+1", "512mb", "1t", "1 m", "9223372036854775807k"} {
+		_, err := generate.ParseMemoryString(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSaveBundle(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetRootPath("rootfs")
+	g.SetProcessArgs([]string{"/bin/busybox", "true"})
+
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "etc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "etc", "hostname"), []byte("bundle\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("hostname", filepath.Join(src, "etc", "name")); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := t.TempDir()
+	assert.NoError(t, g.SaveBundle(bundle, src, generate.ExportOptions{}))
+	data, err := os.ReadFile(filepath.Join(bundle, "rootfs", "etc", "name"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bundle\n", string(data))
+	data, err = os.ReadFile(filepath.Join(bundle, "config.json"))
+	assert.NoError(t, err)
+	var config rspec.Spec
+	assert.NoError(t, json.Unmarshal(data, &config))
+	assert.Equal(t, "rootfs", config.Root.Path)
+
+	assert.Error(t, g.SaveBundle(t.TempDir(), filepath.Join(src, "missing"), generate.ExportOptions{}))
+	g.SetRootPath("/rootfs")
+	assert.Error(t, g.SaveBundle(t.TempDir(), src, generate.ExportOptions{}))
+	g.SetRootPath("rootfs")
+
+	// The rootfs tarballs used by the validation tests run busybox on the host.
+	tarball := filepath.Join("..", "rootfs-"+runtime.GOARCH+".tar.gz")
+	if _, err := os.Stat(tarball); err != nil || runtime.GOOS != "linux" {
+		t.Skipf("no runnable rootfs tarball for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	bundle = t.TempDir()
+	if err := g.SaveBundle(bundle, tarball, generate.ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(filepath.Join(bundle, "rootfs", "bin", "busybox"), "echo", "ok").CombinedOutput()
+	assert.NoError(t, err)
+	assert.Equal(t, "ok\n", string(out))
+}
+
+func TestSetProcessArgs(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{nil, {}, {""}, {" \t", "ls"}} {
+		err := g.SetProcessArgs(args)
+		if assert.Error(t, err, "%q", args) {
+			assert.Equal(t, specerror.ProcArgsOneEntryRequired, err.(*specerror.Error).Code)
+		}
+	}
+	assert.Equal(t, []string{"sh"}, g.Config.Process.Args)
+
+	assert.NoError(t, g.SetProcessArgs([]string{"ls", ""}))
+	assert.Equal(t, []string{"ls", ""}, g.Config.Process.Args)
+
+	g.Config.Process.Args = []string{" "}
+	errs := g.Validate()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, specerror.ProcArgsOneEntryRequired, errs[0].(*specerror.Error).Code)
+	}
+}
+
+func TestAddLinuxSeccompSyscall(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Config.Linux.Seccomp = nil
+
+	// Allow ioctl with TIOCGWINSZ only.
+	ioctl := rspec.LinuxSyscall{
+		Names:  []string{"ioctl"},
+		Action: rspec.ActAllow,
+		Args: []rspec.LinuxSeccompArg{
+			{Index: 1, Value: 0x5413, Op: rspec.OpEqualTo},
+		},
+	}
+	assert.NoError(t, g.AddLinuxSeccompSyscall(ioctl))
+	assert.Equal(t, []rspec.LinuxSyscall{ioctl}, g.Config.Linux.Seccomp.Syscalls)
+
+	for _, syscall := range []rspec.LinuxSyscall{
+		{Action: rspec.ActAllow},
+		{Names: []string{"ioctl"}, Action: "SCMP_ACT_UNKNOWN"},
+		{Names: []string{"ioctl"}, Action: rspec.ActAllow, Args: []rspec.LinuxSeccompArg{{Index: 6, Op: rspec.OpEqualTo}}},
+		{Names: []string{"ioctl"}, Action: rspec.ActAllow, Args: []rspec.LinuxSeccompArg{{Index: 1, Op: "SCMP_CMP_UNKNOWN"}}},
+	} {
+		assert.Error(t, g.AddLinuxSeccompSyscall(syscall), "%+v", syscall)
+	}
+	assert.Len(t, g.Config.Linux.Seccomp.Syscalls, 1)
+}
+
+func TestMaskProcPaths(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	g.AddLinuxMaskedPaths("/proc/kcore")
+	g.AddLinuxMaskedPaths("/proc/acpi")
+	g.MaskProcPaths()
+	g.MaskProcPaths()
+	assert.Equal(t, []string{
+		"/proc/kcore",
+		"/proc/acpi",
+		"/proc/latency_stats",
+		"/proc/timer_list",
+		"/proc/sched_debug",
+		"/proc/scsi",
+	}, g.Config.Linux.MaskedPaths)
+}
+
+func TestSaveProcess(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, g.SetProcessArgs([]string{"ps", "aux"}))
+	g.AddProcessEnv("FOO", "bar")
+	g.SetProcessTerminal(true)
+	assert.Empty(t, g.ValidateProcess())
+
+	process := g.ExecProcess()
+	process.Args[0] = "top"
+	assert.Equal(t, "ps", g.Config.Process.Args[0])
+
+	path := filepath.Join(t.TempDir(), "process.json")
+	assert.NoError(t, g.SaveProcess(path))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var saved rspec.Process
+	assert.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, *g.Config.Process, saved)
+
+	g.SetProcessCwd("relative")
+	errs := g.ValidateProcess()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, specerror.ProcCwdAbs, errs[0].(*specerror.Error).Code)
+	}
+
+	g.Config.Process = nil
+	assert.Nil(t, g.ExecProcess())
+	assert.Error(t, g.SaveProcess(path))
+}
+
+func TestSetLinuxResourcesMemoryHighLow(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	assert.NoError(t, g.SetLinuxResourcesMemoryHigh("512m"))
+	assert.NoError(t, g.SetLinuxResourcesMemoryLow("1048576"))
+	assert.Equal(t, map[string]string{"memory.high": "536870912", "memory.low": "1048576"}, g.Config.Linux.Resources.Unified)
+
+	assert.NoError(t, g.SetLinuxResourcesMemoryHigh("max"))
+	assert.Equal(t, "max", g.Config.Linux.Resources.Unified["memory.high"])
+
+	assert.Error(t, g.SetLinuxResourcesMemoryLow("-1"))
+	assert.Error(t, g.SetLinuxResourcesMemoryLow("MAX"))
+	assert.Equal(t, "1048576", g.Config.Linux.Resources.Unified["memory.low"])
+}
+
+func TestAddProcessCapabilityAmbient(t *testing.T) {
+	g, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ClearProcessCapabilities()
+	assert.NoError(t, g.AddProcessCapabilityAmbient("cap_net_raw"))
+	caps := g.Config.Process.Capabilities
+	assert.Equal(t, []string{"CAP_NET_RAW"}, caps.Ambient)
+	assert.Equal(t, []string{"CAP_NET_RAW"}, caps.Permitted)
+	assert.Equal(t, []string{"CAP_NET_RAW"}, caps.Inheritable)
+	assert.Empty(t, caps.Bounding)
+	assert.Empty(t, caps.Effective)
+	assert.Empty(t, g.Validate())
+
+	assert.NoError(t, g.AddProcessCapabilityAmbientOnly("CAP_CHOWN"))
+	assert.Equal(t, []string{"CAP_NET_RAW", "CAP_CHOWN"}, caps.Ambient)
+	assert.Equal(t, []string{"CAP_NET_RAW"}, caps.Permitted)
+	errs := g.Validate()
+	if assert.Len(t, errs, 2) {
+		for _, err := range errs {
+			assert.Equal(t, specerror.LinuxProcCapError, err.(*specerror.Error).Code)
+		}
+	}
+}
+
+func TestAddProcessRlimitBoth(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	g.AddProcessRlimitBoth("RLIMIT_NOFILE", 1024)
+	g.AddProcessRlimitBoth("RLIMIT_NPROC", 64)
+	g.AddProcessRlimitBoth("RLIMIT_NOFILE", 4096)
+
+	expected := generate.NewFromSpec(&rspec.Spec{})
+	expected.AddProcessRlimits("RLIMIT_NOFILE", 1024, 1024)
+	expected.AddProcessRlimits("RLIMIT_NPROC", 64, 64)
+	expected.AddProcessRlimits("RLIMIT_NOFILE", 4096, 4096)
+	assert.Equal(t, expected.Config.Process.Rlimits, g.Config.Process.Rlimits)
+	assert.Len(t, g.Config.Process.Rlimits, 2)
+}
+
+func TestAddProcessEnvExpanded(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	g.AddProcessEnv("PATH", "/usr/bin:/bin")
+	assert.NoError(t, g.AddProcessEnvExpanded("PATH=$PATH:/opt/bin"))
+	assert.NoError(t, g.AddProcessEnvExpanded("BASE=/srv"))
+	assert.NoError(t, g.AddProcessEnvExpanded("DATA=${BASE}/data"))
+	assert.NoError(t, g.AddProcessEnvExpanded("CACHE=$DATA/cache"))
+	assert.NoError(t, g.AddProcessEnvExpanded("EMPTY=[$UNSET]"))
+	assert.Equal(t, []string{
+		"PATH=/usr/bin:/bin:/opt/bin",
+		"BASE=/srv",
+		"DATA=/srv/data",
+		"CACHE=/srv/data/cache",
+		"EMPTY=[]",
+	}, g.Config.Process.Env)
+
+	assert.Error(t, g.AddProcessEnvExpandedStrict("OTHER=$UNSET/x"))
+	assert.NoError(t, g.AddProcessEnvExpandedStrict("OTHER=$CACHE/x"))
+	assert.Error(t, g.AddProcessEnvExpanded("NOVALUE"))
+	assert.Error(t, g.AddProcessEnvExpanded("=value"))
+	assert.Len(t, g.Config.Process.Env, 6)
+}
+
+func TestAddLinuxSysctlFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sysctl.conf")
+	conf := `# Tuning for the container
+net.ipv4.ip_forward = 1
+; another comment
+net/ipv4/conf/eth0.100/forwarding=0
+  kernel.msgmax   =   65536
+-net.core.somaxconn = 1024
+`
+	if err := os.WriteFile(path, []byte(conf), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := generate.NewFromSpec(&rspec.Spec{})
+	assert.NoError(t, g.AddLinuxSysctlFromFile(path))
+	assert.Equal(t, map[string]string{
+		"net.ipv4.ip_forward":               "1",
+		"net.ipv4.conf.eth0/100.forwarding": "0",
+		"kernel.msgmax":                     "65536",
+		"net.core.somaxconn":                "1024",
+	}, g.Config.Linux.Sysctl)
+
+	if err := os.WriteFile(path, []byte("net.ipv4.ip_forward\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, g.AddLinuxSysctlFromFile(path))
+	assert.Error(t, g.AddLinuxSysctlFromFile(filepath.Join(t.TempDir(), "missing.conf")))
+}
+
+func TestAddMountInferred(t *testing.T) {
+	for _, c := range []struct {
+		kind     string
+		mnt      rspec.Mount
+		expected rspec.Mount
+	}{
+		{
+			"bind from a path",
+			rspec.Mount{Destination: "/data", Source: "/srv/data", Options: []string{"ro"}},
+			rspec.Mount{Destination: "/data", Type: "bind", Source: "/srv/data", Options: []string{"rbind", "ro"}},
+		},
+		{
+			"non-recursive bind",
+			rspec.Mount{Destination: "/data", Source: "data", Options: []string{"bind"}},
+			rspec.Mount{Destination: "/data", Type: "bind", Source: "data", Options: []string{"bind"}},
+		},
+		{
+			"tmpfs from its source",
+			rspec.Mount{Destination: "/run", Source: "tmpfs", Options: []string{"size=64m"}},
+			rspec.Mount{Destination: "/run", Type: "tmpfs", Source: "tmpfs", Options: []string{"size=64m"}},
+		},
+		{
+			"proc without a source",
+			rspec.Mount{Destination: "/proc", Type: "proc"},
+			rspec.Mount{Destination: "/proc", Type: "proc", Source: "proc"},
+		},
+		{
+			"block device",
+			rspec.Mount{Destination: "/mnt", Type: "ext4", Source: "/dev/sdb1"},
+			rspec.Mount{Destination: "/mnt", Type: "ext4", Source: "/dev/sdb1"},
+		},
+	} {
+		g := generate.NewFromSpec(&rspec.Spec{})
+		assert.NoError(t, g.AddMountInferred(c.mnt), c.kind)
+		assert.Equal(t, []rspec.Mount{c.expected}, g.Config.Mounts, c.kind)
+	}
+
+	for _, c := range []struct {
+		kind string
+		mnt  rspec.Mount
+	}{
+		{"bind without a source", rspec.Mount{Destination: "/data", Type: "bind"}},
+		{"bind of a pseudo filesystem", rspec.Mount{Destination: "/data", Type: "tmpfs", Source: "tmpfs", Options: []string{"bind"}}},
+		{"tmpfs with a path", rspec.Mount{Destination: "/run", Type: "tmpfs", Source: "/srv/run"}},
+		{"block device without a source", rspec.Mount{Destination: "/mnt", Type: "ext4"}},
+		{"nothing to infer from", rspec.Mount{Destination: "/mnt", Source: "volume"}},
+		{"relative destination", rspec.Mount{Destination: "run", Source: "tmpfs"}},
+	} {
+		g := generate.NewFromSpec(&rspec.Spec{})
+		assert.Error(t, g.AddMountInferred(c.mnt), c.kind)
+		assert.Empty(t, g.Config.Mounts, c.kind)
+	}
+}
+
+func TestStartTimeout(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	assert.Equal(t, 0, g.StartTimeout())
+	assert.NoError(t, g.SetStartTimeout(30))
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.Save(&buf, generate.ExportOptions{}))
+	var config rspec.Spec
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &config))
+	assert.Equal(t, "30", config.Annotations[generate.AnnotationStartTimeout])
+	loaded := generate.NewFromSpec(&config)
+	assert.Equal(t, 30, loaded.StartTimeout())
+
+	assert.Error(t, g.SetStartTimeout(0))
+	assert.Error(t, g.SetStartTimeout(-5))
+	assert.Equal(t, 30, g.StartTimeout())
+
+	g.ClearStartTimeout()
+	assert.Equal(t, 0, g.StartTimeout())
+}
+
+func TestSetLinuxResourcesPidsLimitUnlimited(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	g.SetLinuxResourcesPidsLimitUnlimited()
+	assert.Nil(t, g.Config.Linux)
+
+	g.SetLinuxResourcesPidsLimit(100)
+	g.SetLinuxResourcesPidsLimitUnlimited()
+	assert.Nil(t, g.Config.Linux.Resources.Pids)
+}
+
+func TestSaveReproducible(t *testing.T) {
+	build := func(reverse bool) []byte {
+		g := generate.NewFromSpec(&rspec.Spec{})
+		steps := []func(){
+			func() { g.AddAnnotation("org.example.a", "1") },
+			func() { g.AddAnnotation("org.example.b", "2") },
+			func() { g.AddLinuxSysctl("net.ipv4.ip_forward", "1") },
+			func() { g.AddLinuxSysctl("kernel.shmmax", "4096") },
+			func() { g.AddLinuxResourcesUnified("memory.high", "1048576") },
+			func() { g.AddLinuxResourcesUnified("cpu.weight", "100") },
+			func() { g.AddLinuxResourcesHugepageLimit("2MB", 1024) },
+			func() { g.AddLinuxResourcesHugepageLimit("1GB", 0) },
+			func() { assert.NoError(t, g.AddProcessCapabilityBounding("CAP_NET_ADMIN")) },
+			func() { assert.NoError(t, g.AddProcessCapabilityBounding("CAP_CHOWN")) },
+		}
+		for i := range steps {
+			if reverse {
+				i = len(steps) - 1 - i
+			}
+			steps[i]()
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, g.Save(&buf, generate.ExportOptions{Reproducible: true}))
+		return buf.Bytes()
+	}
+
+	assert.Equal(t, string(build(false)), string(build(true)))
+}
+
+func TestSetMaxAllowedCapabilities(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	assert.NoError(t, g.SetMaxAllowedCapabilities([]string{"cap_chown", "CAP_KILL"}))
+	assert.NoError(t, g.AddProcessCapability("CAP_CHOWN"))
+	assert.NoError(t, g.AddProcessCapabilityBounding("CAP_KILL"))
+	assert.Error(t, g.AddProcessCapability("CAP_SYS_ADMIN"))
+	assert.Error(t, g.AddProcessCapabilityAmbient("CAP_NET_ADMIN"))
+	assert.Equal(t, []string{"CAP_CHOWN", "CAP_KILL"}, g.Config.Process.Capabilities.Bounding)
+	assert.Equal(t, []string{"CAP_CHOWN"}, g.Config.Process.Capabilities.Ambient)
+
+	g.SetDropDisallowedCapabilities(true)
+	assert.NoError(t, g.AddProcessCapabilityEffective("CAP_SYS_ADMIN"))
+	assert.Equal(t, []string{"CAP_CHOWN"}, g.Config.Process.Capabilities.Effective)
+
+	assert.NoError(t, g.SetMaxAllowedCapabilities(nil))
+	assert.NoError(t, g.AddProcessCapabilityEffective("CAP_SYS_ADMIN"))
+	assert.Equal(t, []string{"CAP_CHOWN", "CAP_SYS_ADMIN"}, g.Config.Process.Capabilities.Effective)
+
+	assert.Error(t, g.SetMaxAllowedCapabilities([]string{"CAP_NOT_A_CAP"}))
+}
+
+func TestAddProcessCapabilitiesFromProc(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc/<pid>/status is Linux-only")
+	}
+	g := generate.NewFromSpec(&rspec.Spec{})
+	assert.NoError(t, g.AddProcessCapabilitiesFromProc(os.Getpid()))
+
+	self, err := capability.NewPid2(0)
+	assert.NoError(t, err)
+	assert.NoError(t, self.Load())
+	caps := g.Config.Process.Capabilities
+	for _, set := range []struct {
+		which capability.CapType
+		caps  []string
+	}{
+		{capability.BOUNDING, caps.Bounding},
+		{capability.EFFECTIVE, caps.Effective},
+		{capability.INHERITABLE, caps.Inheritable},
+		{capability.PERMITTED, caps.Permitted},
+		{capability.AMBIENT, caps.Ambient},
+	} {
+		var expected []string
+		for _, c := range capability.List() {
+			if self.Get(set.which, c) {
+				expected = append(expected, "CAP_"+strings.ToUpper(c.String()))
+			}
+		}
+		assert.ElementsMatch(t, expected, set.caps, set.which.String())
+	}
+
+	assert.Error(t, g.AddProcessCapabilitiesFromProc(-1))
+}
+
+func TestSetLinuxResourcesCPUBurst(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	g.ClearLinuxResourcesCPUBurst()
+	assert.Nil(t, g.Config.Linux)
+
+	assert.NoError(t, g.SetLinuxResourcesCPUBurst(50000))
+	assert.Equal(t, uint64(50000), *g.Config.Linux.Resources.CPU.Burst)
+
+	g.SetLinuxResourcesCPUQuota(20000)
+	assert.Error(t, g.SetLinuxResourcesCPUBurst(30000))
+	assert.NotEmpty(t, g.Validate())
+	assert.NoError(t, g.SetLinuxResourcesCPUBurst(20000))
+	assert.Empty(t, g.Validate())
+
+	g.ClearLinuxResourcesCPUBurst()
+	assert.Nil(t, g.Config.Linux.Resources.CPU.Burst)
+}
+
+func TestAddAnnotationsFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "annotations")
+	assert.NoError(t, os.WriteFile(path, []byte("# build metadata\norg.example.commit=abc123\n\n  org.example.owner=team a \r\norg.example.args=a=b\norg.example.commit=def456\n"), 0o644))
+
+	g := generate.NewFromSpec(&rspec.Spec{})
+	g.AddAnnotation("org.example.owner", "nobody")
+	assert.NoError(t, g.AddAnnotationsFromFile(path))
+	assert.Equal(t, map[string]string{
+		"org.example.commit": "def456",
+		"org.example.owner":  "team a ",
+		"org.example.args":   "a=b",
+	}, g.Config.Annotations)
+
+	for _, invalid := range []string{"=value\n", "org.example.flag\n", "org example=1\n"} {
+		assert.NoError(t, os.WriteFile(path, []byte("org.example.valid=1\n"+invalid), 0o644))
+		g := generate.NewFromSpec(&rspec.Spec{})
+		assert.Error(t, g.AddAnnotationsFromFile(path), invalid)
+		assert.Empty(t, g.Config.Annotations)
+	}
+
+	assert.Error(t, g.AddAnnotationsFromFile(filepath.Join(dir, "missing")))
+}
+
+func TestNewMinimal(t *testing.T) {
+	g := generate.NewMinimal()
+	assert.Empty(t, g.Validate())
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.Save(&buf, generate.ExportOptions{}))
+	var config map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &config))
+	assert.ElementsMatch(t, []string{"ociVersion", "process", "root"}, keys(config))
+	process := config["process"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"args", "cwd", "user"}, keys(process))
+	assert.Equal(t, map[string]interface{}{"path": "rootfs"}, config["root"])
+}
+
+func keys(m map[string]interface{}) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestRemoveLinuxIDMapping(t *testing.T) {
+	g := generate.NewFromSpec(&rspec.Spec{})
+	g.RemoveLinuxUIDMapping(0)
+	g.RemoveLinuxGIDMapping(0)
+	assert.Nil(t, g.Config.Linux)
+
+	g.AddLinuxUIDMapping(1000, 0, 1)
+	g.AddLinuxUIDMapping(100000, 1, 65536)
+	g.AddLinuxGIDMapping(1000, 0, 1)
+	g.AddLinuxGIDMapping(100000, 1, 65536)
+
+	g.RemoveLinuxUIDMapping(1)
+	assert.Equal(t, []rspec.LinuxIDMapping{{HostID: 1000, ContainerID: 0, Size: 1}}, g.Config.Linux.UIDMappings)
+	g.RemoveLinuxGIDMapping(0)
+	assert.Equal(t, []rspec.LinuxIDMapping{{HostID: 100000, ContainerID: 1, Size: 65536}}, g.Config.Linux.GIDMappings)
+
+	g.RemoveLinuxUIDMapping(42)
+	assert.Len(t, g.Config.Linux.UIDMappings, 1)
+}
+
+func TestSetLinuxNamespacesProfile(t *testing.T) {
+	for profile, expected := range map[string][]rspec.LinuxNamespaceType{
+		"default":        {rspec.PIDNamespace, rspec.NetworkNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"full-isolation": {rspec.PIDNamespace, rspec.NetworkNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace, rspec.UserNamespace, rspec.CgroupNamespace},
+		"host-network":   {rspec.PIDNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"host-pid":       {rspec.NetworkNamespace, rspec.IPCNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"host-ipc":       {rspec.PIDNamespace, rspec.NetworkNamespace, rspec.UTSNamespace, rspec.MountNamespace},
+		"host":           {rspec.MountNamespace},
+	} {
+		g := generate.NewFromSpec(&rspec.Spec{})
+		assert.NoError(t, g.AddOrReplaceLinuxNamespace("network", "/proc/1/ns/net"))
+		assert.NoError(t, g.SetLinuxNamespacesProfile(profile), profile)
+		var actual []rspec.LinuxNamespaceType
+		for _, ns := range g.Config.Linux.Namespaces {
+			assert.Empty(t, ns.Path, profile)
+			actual = append(actual, ns.Type)
+		}
+		assert.Equal(t, expected, actual, profile)
+		assert.Empty(t, g.Validate(), profile)
+	}
+
+	g := generate.NewFromSpec(&rspec.Spec{})
+	assert.Error(t, g.SetLinuxNamespacesProfile("host-everything"))
+	assert.Nil(t, g.Config.Linux)
+}
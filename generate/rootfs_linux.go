@@ -0,0 +1,30 @@
+//go:build linux
+// +build linux
+
+package generate
+
+import "golang.org/x/sys/unix"
+
+// lockedMountFlags returns the nosuid, nodev and noexec flags set on the
+// host mount holding path. A mount created in a user namespace is locked
+// with these, so any remount has to keep them.
+func lockedMountFlags(path string) []string {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return nil
+	}
+	var flags []string
+	for _, f := range []struct {
+		flag int64
+		name string
+	}{
+		{unix.ST_NOSUID, "nosuid"},
+		{unix.ST_NODEV, "nodev"},
+		{unix.ST_NOEXEC, "noexec"},
+	} {
+		if int64(st.Flags)&f.flag != 0 {
+			flags = append(flags, f.name)
+		}
+	}
+	return flags
+}
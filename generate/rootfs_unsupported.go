@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package generate
+
+// lockedMountFlags returns the nosuid, nodev and noexec flags set on the
+// host mount holding path. It always returns nil off Linux.
+func lockedMountFlags(path string) []string {
+	return nil
+}
@@ -24,6 +24,9 @@ func parseArguments(delimArgs []string) ([]rspec.LinuxSeccompArg, error) {
 		if err != nil {
 			return nilArgSlice, err
 		}
+		if syscallIndex > maxArgIndex {
+			return nilArgSlice, fmt.Errorf("syscall argument index %d is out of range [0-%d]", syscallIndex, maxArgIndex)
+		}
 
 		syscallValue, err := strconv.ParseUint(delimArgs[2], 10, 64)
 		if err != nil {
@@ -55,6 +58,24 @@ func parseArguments(delimArgs []string) ([]rspec.LinuxSeccompArg, error) {
 	return nilArgSlice, fmt.Errorf("incorrect number of arguments passed with syscall: %d", numberOfArgs)
 }
 
+// maxArgIndex is the highest syscall argument index seccomp can filter on.
+const maxArgIndex = 5
+
+// CheckArg returns an error if arg filters on an argument index above 5 or
+// uses an operator other than the SCMP_CMP_* ones.
+func CheckArg(arg rspec.LinuxSeccompArg) error {
+	if arg.Index > maxArgIndex {
+		return fmt.Errorf("syscall argument index %d is out of range [0-%d]", arg.Index, maxArgIndex)
+	}
+	switch arg.Op {
+	case rspec.OpNotEqual, rspec.OpLessThan, rspec.OpLessEqual, rspec.OpEqualTo,
+		rspec.OpGreaterEqual, rspec.OpGreaterThan, rspec.OpMaskedEqual:
+	default:
+		return fmt.Errorf("unrecognized operator: %s", arg.Op)
+	}
+	return nil
+}
+
 func parseOperator(operator string) (rspec.LinuxSeccompOperator, error) {
 	operators := map[string]rspec.LinuxSeccompOperator{
 		"NE": rspec.OpNotEqual,
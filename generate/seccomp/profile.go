@@ -0,0 +1,145 @@
+package seccomp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// dockerProfile is the seccomp profile format used by Docker and moby.
+type dockerProfile struct {
+	DefaultAction   rspec.LinuxSeccompAction `json:"defaultAction"`
+	DefaultErrnoRet *uint                    `json:"defaultErrnoRet,omitempty"`
+	Architectures   []rspec.Arch             `json:"architectures,omitempty"`
+	ArchMap         []dockerArchitecture     `json:"archMap,omitempty"`
+	Flags           []rspec.LinuxSeccompFlag `json:"flags,omitempty"`
+	Syscalls        []dockerSyscall          `json:"syscalls"`
+}
+
+type dockerArchitecture struct {
+	Arch      rspec.Arch   `json:"architecture"`
+	SubArches []rspec.Arch `json:"subArchitectures"`
+}
+
+type dockerSyscall struct {
+	Name     string                   `json:"name,omitempty"`
+	Names    []string                 `json:"names,omitempty"`
+	Action   rspec.LinuxSeccompAction `json:"action"`
+	ErrnoRet *uint                    `json:"errnoRet,omitempty"`
+	Args     []rspec.LinuxSeccompArg  `json:"args"`
+	Comment  string                   `json:"comment"`
+	Includes dockerFilter             `json:"includes"`
+	Excludes dockerFilter             `json:"excludes"`
+}
+
+// dockerFilter restricts a syscall rule to the listed architectures (Go
+// GOARCH names) and capabilities. MinKernel is parsed but not checked, since
+// the profile may be used on a different host than the one generating the
+// config.
+type dockerFilter struct {
+	Arches    []string `json:"arches,omitempty"`
+	Caps      []string `json:"caps,omitempty"`
+	MinKernel string   `json:"minKernel,omitempty"`
+}
+
+// LoadProfile reads a Docker-format seccomp profile from path and converts
+// it to a LinuxSeccomp for the native architecture, assuming no capabilities
+// are granted. Use ParseProfile to filter for a specific capability set.
+func LoadProfile(path string) (*rspec.LinuxSeccomp, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProfile(data, runtime.GOARCH, nil)
+}
+
+// ParseProfile converts a Docker-format seccomp profile to a LinuxSeccomp.
+// Rules whose includes or excludes do not match arch (a GOARCH name) and
+// caps are dropped, and the architecture map is reduced to arch and its
+// sub-architectures.
+func ParseProfile(data []byte, arch string, caps []string) (*rspec.LinuxSeccomp, error) {
+	var profile dockerProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("decoding seccomp profile: %w", err)
+	}
+	if len(profile.Architectures) > 0 && len(profile.ArchMap) > 0 {
+		return nil, fmt.Errorf("seccomp profile cannot set both architectures and archMap")
+	}
+
+	config := &rspec.LinuxSeccomp{
+		DefaultAction:   profile.DefaultAction,
+		DefaultErrnoRet: profile.DefaultErrnoRet,
+		Architectures:   profile.Architectures,
+		Flags:           profile.Flags,
+	}
+
+	if len(profile.ArchMap) > 0 {
+		native, err := parseArch(arch)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range profile.ArchMap {
+			if a.Arch == native {
+				config.Architectures = append(config.Architectures, a.Arch)
+				config.Architectures = append(config.Architectures, a.SubArches...)
+			}
+		}
+	}
+
+	for _, call := range profile.Syscalls {
+		if call.Name != "" && len(call.Names) > 0 {
+			return nil, fmt.Errorf("seccomp syscall rule cannot set both name and names")
+		}
+		if !filterMatches(call, arch, caps) {
+			continue
+		}
+
+		names := call.Names
+		if call.Name != "" {
+			names = []string{call.Name}
+		}
+		config.Syscalls = append(config.Syscalls, rspec.LinuxSyscall{
+			Names:    names,
+			Action:   call.Action,
+			ErrnoRet: call.ErrnoRet,
+			Args:     call.Args,
+		})
+	}
+
+	return config, nil
+}
+
+// filterMatches reports whether call applies to arch and caps. Every
+// included architecture list and capability must match, while any excluded
+// architecture or capability drops the rule.
+func filterMatches(call dockerSyscall, arch string, caps []string) bool {
+	if len(call.Includes.Arches) > 0 && !inSlice(call.Includes.Arches, arch) {
+		return false
+	}
+	for _, c := range call.Includes.Caps {
+		if !inSlice(caps, c) {
+			return false
+		}
+	}
+	if inSlice(call.Excludes.Arches, arch) {
+		return false
+	}
+	for _, c := range call.Excludes.Caps {
+		if inSlice(caps, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func inSlice(slice []string, s string) bool {
+	for _, ss := range slice {
+		if s == ss {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubID(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		data     string
+		expected []rspec.LinuxIDMapping
+		err      string
+	}{
+		{
+			name:     "match by name",
+			data:     "other:200000:65536\nalice:100000:65536\n",
+			expected: []rspec.LinuxIDMapping{{ContainerID: 1, HostID: 100000, Size: 65536}},
+		},
+		{
+			name:     "match by uid",
+			data:     "# comment\n1000:100000:65536\n",
+			expected: []rspec.LinuxIDMapping{{ContainerID: 1, HostID: 100000, Size: 65536}},
+		},
+		{
+			name: "multiple ranges",
+			data: "alice:100000:1000\nother:200000:65536\n1000:300000:500\n",
+			expected: []rspec.LinuxIDMapping{
+				{ContainerID: 1, HostID: 100000, Size: 1000},
+				{ContainerID: 1001, HostID: 300000, Size: 500},
+			},
+		},
+		{
+			name: "no allocation",
+			data: "other:200000:65536\n",
+			err:  "subuid: no subordinate ids allocated to alice",
+		},
+		{
+			name: "malformed line",
+			data: "alice:100000\n",
+			err:  `subuid: invalid entry "alice:100000"`,
+		},
+		{
+			name: "malformed start",
+			data: "alice:start:65536\n",
+			err:  `subuid: invalid start in entry "alice:start:65536"`,
+		},
+		{
+			name: "container end overflow",
+			data: "alice:0:4294967295\nalice:100000:1\n",
+			err:  `subuid: entry "alice:100000:1" maps beyond the largest container id`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mappings, err := parseSubID(strings.NewReader(tt.data), "subuid", "alice", "1000")
+			if tt.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.err)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, mappings)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package generate
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	osFilepath "github.com/opencontainers/runtime-tools/filepath"
+	"github.com/opencontainers/runtime-tools/specerror"
+	capsCheck "github.com/opencontainers/runtime-tools/validate/capabilities"
+)
+
+// Validate runs structural checks on g.Config before it is saved, returning
+// a spec-coded error for each problem found. It is much lighter than the
+// validate package and does not need a bundle on disk.
+func (g *Generator) Validate() []error {
+	if g.Config == nil {
+		return nil
+	}
+
+	var errs []error
+	errs = append(errs, g.validateProcess()...)
+	for _, mnt := range g.Config.Mounts {
+		if err := g.checkMountDestination(mnt); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.Config.Linux != nil {
+		errs = append(errs, g.validateLinuxNamespaces()...)
+		errs = append(errs, g.validateLinuxResources()...)
+	}
+	return errs
+}
+
+// ValidateProcess runs the checks of Validate on g.Config.Process alone, for
+// a process saved with SaveProcess.
+func (g *Generator) ValidateProcess() []error {
+	if g.Config == nil {
+		return nil
+	}
+	return g.validateProcess()
+}
+
+// Warnings returns advice on g.Config which no spec requirement covers:
+// settings that are valid, but unlikely to do what was meant. Unlike the
+// errors from Validate, they do not make the configuration invalid.
+func (g *Generator) Warnings() (warnings []string) {
+	if g.Config == nil {
+		return nil
+	}
+
+	if process := g.Config.Process; process != nil {
+		if process.NoNewPrivileges && process.User.UID != 0 && process.Capabilities != nil && len(process.Capabilities.Ambient) > 0 {
+			warnings = append(warnings, fmt.Sprintf("process.capabilities.ambient is ineffective for non-root uid %d with process.noNewPrivileges", process.User.UID))
+		}
+	}
+	if w := g.rootWarning(); w != "" {
+		warnings = append(warnings, w)
+	}
+	if g.Config.Linux != nil && g.Config.Linux.Resources != nil {
+		if m := g.Config.Linux.Resources.Memory; m != nil && m.Limit != nil && *m.Limit > 0 && m.Reservation != nil && *m.Reservation > *m.Limit {
+			warnings = append(warnings, fmt.Sprintf("memory reservation %d is above the memory limit %d", *m.Reservation, *m.Limit))
+		}
+	}
+	return warnings
+}
+
+func (g *Generator) validateProcess() (errs []error) {
+	process := g.Config.Process
+	if process == nil {
+		return nil
+	}
+
+	if err := checkProcessArgs(process.Args); err != nil {
+		errs = append(errs, err)
+	}
+	if !osFilepath.IsAbs(g.platform(), process.Cwd) {
+		errs = append(errs, specerror.NewError(specerror.ProcCwdAbs, fmt.Errorf("process.cwd %q is not an absolute path", process.Cwd), rspec.Version))
+	}
+
+	if process.Capabilities != nil {
+		caps := process.Capabilities
+		for _, set := range []struct {
+			name string
+			caps []string
+		}{
+			{"bounding", caps.Bounding},
+			{"effective", caps.Effective},
+			{"inheritable", caps.Inheritable},
+			{"permitted", caps.Permitted},
+			{"ambient", caps.Ambient},
+		} {
+			for _, c := range set.caps {
+				if err := capsCheck.CapValid(c, g.HostSpecific); err != nil {
+					errs = append(errs, specerror.NewError(specerror.LinuxProcCapError, fmt.Errorf("process.capabilities.%s: %w", set.name, err), rspec.Version))
+				}
+			}
+		}
+	}
+
+	if caps := process.Capabilities; caps != nil {
+		for _, c := range caps.Ambient {
+			for _, set := range []struct {
+				name string
+				caps []string
+			}{
+				{"permitted", caps.Permitted},
+				{"inheritable", caps.Inheritable},
+			} {
+				found := false
+				for _, sc := range set.caps {
+					if strings.EqualFold(sc, c) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					errs = append(errs, specerror.NewError(specerror.LinuxProcCapError, fmt.Errorf("ambient capability %s is not in process.capabilities.%s, as the kernel requires", c, set.name), rspec.Version))
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(process.Rlimits))
+	for _, rlimit := range process.Rlimits {
+		if seen[rlimit.Type] {
+			errs = append(errs, specerror.NewError(specerror.PosixProcRlimitsErrorOnDup, fmt.Errorf("process.rlimits has duplicated entries of type %s", rlimit.Type), rspec.Version))
+		}
+		seen[rlimit.Type] = true
+	}
+
+	return errs
+}
+
+// rootWarning flags a read-only root filesystem that a rootless runtime may
+// fail to set up: inside a user namespace the read-only remount has to keep
+// the flags the host mount is locked with, or it fails with EPERM. This
+// inspects the host, with root.path taken relative to the working directory,
+// so it only runs when g.HostSpecific is set.
+func (g *Generator) rootWarning() string {
+	root := g.Config.Root
+	if root == nil || !root.Readonly || !g.HostSpecific || os.Geteuid() == 0 {
+		return ""
+	}
+	if g.Config.Linux == nil {
+		return ""
+	}
+	userns := false
+	for _, ns := range g.Config.Linux.Namespaces {
+		if ns.Type == rspec.UserNamespace {
+			userns = true
+			break
+		}
+	}
+	if !userns {
+		return ""
+	}
+
+	if flags := lockedMountFlags(root.Path); len(flags) > 0 {
+		return fmt.Sprintf("read-only root.path %q is on a host mount locked with %s, which a rootless runtime has to keep when remounting it", root.Path, strings.Join(flags, ","))
+	}
+	return ""
+}
+
+func (g *Generator) validateLinuxNamespaces() (errs []error) {
+	seen := make(map[rspec.LinuxNamespaceType]bool, len(g.Config.Linux.Namespaces))
+	for _, ns := range g.Config.Linux.Namespaces {
+		switch ns.Type {
+		case rspec.PIDNamespace, rspec.NetworkNamespace, rspec.MountNamespace,
+			rspec.IPCNamespace, rspec.UTSNamespace, rspec.UserNamespace,
+			rspec.CgroupNamespace, rspec.TimeNamespace:
+		default:
+			errs = append(errs, specerror.NewError(specerror.ValidValues, fmt.Errorf("unknown namespace type %q", ns.Type), rspec.Version))
+			continue
+		}
+		if seen[ns.Type] {
+			errs = append(errs, specerror.NewError(specerror.NSErrorOnDup, fmt.Errorf("duplicated namespace %q", ns.Type), rspec.Version))
+		}
+		seen[ns.Type] = true
+	}
+	return errs
+}
+
+func (g *Generator) validateLinuxResources() (errs []error) {
+	r := g.Config.Linux.Resources
+	if r == nil {
+		return nil
+	}
+
+	if m := r.Memory; m != nil && m.Limit != nil && *m.Limit > 0 {
+		if m.Swap != nil && *m.Swap > 0 && *m.Swap < *m.Limit {
+			errs = append(errs, specerror.NewError(specerror.ValidValues, fmt.Errorf("memory+swap limit %d is below the memory limit %d", *m.Swap, *m.Limit), rspec.Version))
+		}
+	}
+
+	if cpu := r.CPU; cpu != nil && cpu.Quota != nil && *cpu.Quota > 0 && cpu.Period != nil && *cpu.Period == 0 {
+		errs = append(errs, specerror.NewError(specerror.ValidValues, fmt.Errorf("cpu quota %d is set with a zero period", *cpu.Quota), rspec.Version))
+	}
+	if cpu := r.CPU; cpu != nil && cpu.Quota != nil && *cpu.Quota > 0 && cpu.Burst != nil && *cpu.Burst > uint64(*cpu.Quota) {
+		errs = append(errs, specerror.NewError(specerror.ValidValues, fmt.Errorf("cpu burst %d exceeds the cpu quota %d", *cpu.Burst, *cpu.Quota), rspec.Version))
+	}
+
+	if b := r.BlockIO; b != nil {
+		if b.Weight != nil && (*b.Weight < 10 || *b.Weight > 1000) {
+			errs = append(errs, specerror.NewError(specerror.ValidValues, fmt.Errorf("blkio weight %d is out of range [10-1000]", *b.Weight), rspec.Version))
+		}
+		if b.LeafWeight != nil && (*b.LeafWeight < 10 || *b.LeafWeight > 1000) {
+			errs = append(errs, specerror.NewError(specerror.ValidValues, fmt.Errorf("blkio leaf weight %d is out of range [10-1000]", *b.LeafWeight), rspec.Version))
+		}
+	}
+
+	return errs
+}
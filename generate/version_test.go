@@ -0,0 +1,45 @@
+package generate
+
+import (
+	"testing"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetSpecVersion(t *testing.T) {
+	for _, version := range []string{"1.0.2", rspec.Version} {
+		burst := uint64(1000)
+		config := &rspec.Spec{
+			Mounts: []rspec.Mount{{
+				Destination: "/data",
+				UIDMappings: []rspec.LinuxIDMapping{{HostID: 1000, Size: 1}},
+			}},
+			Process: &rspec.Process{
+				Args:      []string{"sh"},
+				Scheduler: &rspec.Scheduler{Policy: rspec.SchedOther},
+			},
+			Linux: &rspec.Linux{
+				TimeOffsets: map[string]rspec.LinuxTimeOffset{"monotonic": {Secs: 1}},
+				Resources: &rspec.LinuxResources{
+					CPU: &rspec.LinuxCPU{Burst: &burst},
+				},
+			},
+		}
+		setSpecVersion(config, version)
+
+		assert.Equal(t, version, config.Version)
+		assert.Equal(t, []string{"sh"}, config.Process.Args, version)
+		if version == rspec.Version {
+			assert.NotNil(t, config.Process.Scheduler)
+			assert.NotNil(t, config.Linux.TimeOffsets)
+			assert.NotNil(t, config.Linux.Resources.CPU.Burst)
+			assert.NotNil(t, config.Mounts[0].UIDMappings)
+		} else {
+			assert.Nil(t, config.Process.Scheduler)
+			assert.Nil(t, config.Linux.TimeOffsets)
+			assert.Nil(t, config.Linux.Resources.CPU.Burst)
+			assert.Nil(t, config.Mounts[0].UIDMappings)
+		}
+	}
+}
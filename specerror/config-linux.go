@@ -62,6 +62,8 @@ const (
 	MaskedPathsAbs
 	// ReadonlyPathsAbs represents "readonlyPaths (array of strings, OPTIONAL) will set the provided paths as readonly inside the container. The values MUST be absolute paths in the container namespace."
 	ReadonlyPathsAbs
+	// SeccSyscallsErrnoRet represents "errnoRet (uint, OPTIONAL) - the errno return code to use. Some actions like SCMP_ACT_ERRNO and SCMP_ACT_TRACE allow to specify the errno code to return. When the action doesn't support an errno, the runtime MUST print and error and fail. If not specified then its default value is EPERM."
+	SeccSyscallsErrnoRet
 )
 
 var (
@@ -131,4 +133,5 @@ func init() {
 	register(SeccSyscallsNamesRequired, rfc2119.Must, seccompRef)
 	register(MaskedPathsAbs, rfc2119.Must, maskedPathsRef)
 	register(ReadonlyPathsAbs, rfc2119.Must, readonlyPathsRef)
+	register(SeccSyscallsErrnoRet, rfc2119.Must, seccompRef)
 }
@@ -3,6 +3,7 @@
 package specerror
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
@@ -61,6 +62,26 @@ func (err *Error) Error() string {
 	return err.Err.Error()
 }
 
+// MarshalJSON encodes err with its code, message, compliance level and
+// specification reference, for machine-readable conformance output.
+func (err *Error) MarshalJSON() ([]byte, error) {
+	var message string
+	if err.Err.Err != nil {
+		message = err.Err.Err.Error()
+	}
+	return json.Marshal(struct {
+		Code      Code   `json:"code"`
+		Message   string `json:"message"`
+		Level     string `json:"level"`
+		Reference string `json:"reference"`
+	}{
+		Code:      err.Code,
+		Message:   message,
+		Level:     err.Err.Level.String(),
+		Reference: err.Err.Reference,
+	})
+}
+
 // NewRFCError creates an rfc2119.Error referencing a spec violation.
 //
 // A version string (for the version of the spec that was violated)
@@ -0,0 +1,23 @@
+package specerror
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorMarshalJSON(t *testing.T) {
+	err := NewError(MountsDestAbs, errors.New("destination is not absolute"), "1.1.0")
+	data, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	assert.JSONEq(t, `{
+		"code": 45067,
+		"message": "destination is not absolute",
+		"level": "MUST",
+		"reference": "https://github.com/opencontainers/runtime-spec/blob/v1.1.0/config.md#mounts"
+	}`, string(data))
+}
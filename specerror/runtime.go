@@ -94,6 +94,10 @@ const (
 	DeleteResImplement
 	// DeleteOnlyCreatedRes represents "Note that resources associated with the container, but not created by this container, MUST NOT be deleted."
 	DeleteOnlyCreatedRes
+	// StatePidProcess represents "`pid` (int, REQUIRED when `status` is `created` or `running` on Linux, OPTIONAL on other platforms) is the ID of the container process."
+	StatePidProcess
+	// StateBundleAbs represents "`bundle` (string, REQUIRED) is the absolute path to the container's bundle directory."
+	StateBundleAbs
 )
 
 var (
@@ -176,4 +180,6 @@ func init() {
 	register(DeleteNonStopGenError, rfc2119.Must, deleteRef)
 	register(DeleteResImplement, rfc2119.Must, deleteRef)
 	register(DeleteOnlyCreatedRes, rfc2119.Must, deleteRef)
+	register(StatePidProcess, rfc2119.Required, stateRef)
+	register(StateBundleAbs, rfc2119.Required, stateRef)
 }
@@ -37,7 +37,9 @@ func main() {
 	if err != nil {
 		util.Fatal(err)
 	}
-	g.SetProcessArgs([]string{"/runtimetest", "--path=/test.json"})
+	if err := g.SetProcessArgs([]string{"/runtimetest", "--path=/test.json"}); err != nil {
+		util.Fatal(err)
+	}
 	g.AddLinuxMaskedPaths("/proc/kcore")
 	g.AddLinuxReadonlyPaths("/proc/fs")
 	g.AddLinuxSysctl("net.ipv4.ip_forward", "1")
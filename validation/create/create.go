@@ -22,7 +22,9 @@ func main() {
 		util.Fatal(err)
 	}
 	g.SetRootPath(".")
-	g.SetProcessArgs([]string{"ls"})
+	if err := g.SetProcessArgs([]string{"ls"}); err != nil {
+		util.Fatal(err)
+	}
 
 	bundleDir, err := util.PrepareBundle()
 	if err != nil {
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// newRuntime prepares a bundle with the default config and returns a runtime
+// for it, removing the bundle on failure.
+func newRuntime() (util.Runtime, error) {
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		return util.Runtime{}, err
+	}
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		return util.Runtime{}, err
+	}
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		return util.Runtime{}, err
+	}
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		os.RemoveAll(bundleDir)
+		return util.Runtime{}, err
+	}
+	if err := r.SetConfig(g); err != nil {
+		os.RemoveAll(bundleDir)
+		return util.Runtime{}, err
+	}
+	return r, nil
+}
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	first, err := newRuntime()
+	if err != nil {
+		util.Fatal(err)
+	}
+	defer first.Clean()
+
+	containerID := uuid.NewString()
+	first.SetID(containerID)
+	if err := first.Create(); err != nil {
+		util.Fatal(err)
+	}
+
+	// The second create uses its own bundle, so a runtime which does not
+	// check the ID cannot pass by finding the bundle in use.
+	second, err := newRuntime()
+	if err != nil {
+		util.Fatal(err)
+	}
+	second.SetID(containerID)
+	err = second.Create()
+	util.SpecErrorOK(t, err != nil, specerror.NewError(specerror.CreateWithUniqueID, fmt.Errorf("create MUST generate an error if the ID provided is not unique"), rspecs.Version), err)
+	if err == nil {
+		// The ID now names the second container; the deferred Clean still
+		// removes the first bundle.
+		second.Clean()
+	} else {
+		os.RemoveAll(second.BundleDir)
+	}
+
+	state, err := first.State()
+	if err == nil && (state.Status != rspecs.StateCreated || state.Bundle != first.BundleDir) {
+		err = fmt.Errorf("state is %q for bundle %q, expected %q for bundle %q", state.Status, state.Bundle, rspecs.StateCreated, first.BundleDir)
+	}
+	util.SpecErrorOK(t, err == nil, specerror.NewError(specerror.CreateWithUniqueID, fmt.Errorf("a new container MUST NOT be created if the ID provided is not unique, so the existing container is unaffected"), rspecs.Version), err)
+}
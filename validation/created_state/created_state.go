@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetProcessArgs([]string{"sleep", "10"}); err != nil {
+		util.Fatal(err)
+	}
+
+	config := util.LifecycleConfig{
+		Config:  g,
+		Actions: util.LifecycleActionCreate | util.LifecycleActionStart | util.LifecycleActionDelete,
+		PreCreate: func(r *util.Runtime) error {
+			r.SetID(uuid.NewString())
+			return nil
+		},
+		PostCreate: func(r *util.Runtime) error {
+			// The user-specified program must not run before start, so the
+			// container stays created however long we look.
+			time.Sleep(time.Second)
+			state, err := r.State()
+			if err != nil {
+				return err
+			}
+			util.SpecErrorOK(t, state.Status == rspecs.StateCreated, specerror.NewError(specerror.ProcNotRunAtResRequest, fmt.Errorf("the user-specified program MUST NOT be run before `start`, so the container is %q", rspecs.StateCreated), rspecs.Version), fmt.Errorf("status is %q", state.Status))
+			return nil
+		},
+		PreDelete: func(r *util.Runtime) error {
+			state, err := r.State()
+			if err != nil {
+				return err
+			}
+			started := state.Status == rspecs.StateRunning || state.Status == rspecs.StateStopped
+			util.SpecErrorOK(t, started, specerror.NewError(specerror.StartProcImplement, fmt.Errorf("`start` operation MUST run the user-specified program, so the container is %q or %q", rspecs.StateRunning, rspecs.StateStopped), rspecs.Version), fmt.Errorf("status is %q", state.Status))
+
+			if err := r.Kill("KILL"); err != nil {
+				return err
+			}
+			return util.WaitingForStatus(*r, util.LifecycleStatusStopped, time.Second*10, time.Second)
+		},
+	}
+
+	err = util.RuntimeLifecycleValidate(config)
+	if err != nil {
+		t.Fail(err.Error())
+	}
+}
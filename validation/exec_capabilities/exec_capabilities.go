@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrunalp/fileutils"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	defer r.Clean()
+
+	// The container process gets several more capabilities than the
+	// exec'd one.
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetProcessArgs([]string{"sleep", "30"}); err != nil {
+		util.Fatal(err)
+	}
+	for _, c := range []string{"CAP_SYS_ADMIN", "CAP_NET_ADMIN", "CAP_SYS_PTRACE"} {
+		if err := g.AddProcessCapability(c); err != nil {
+			util.Fatal(err)
+		}
+	}
+	if err := r.SetConfig(g); err != nil {
+		util.Fatal(err)
+	}
+	if err := fileutils.CopyFile("runtimetest", filepath.Join(r.BundleDir, "runtimetest")); err != nil {
+		util.Fatal(err)
+	}
+
+	if err := g.SetProcessArgs([]string{"/runtimetest", "--path=/", "--exec", "--exec-process=/exec-process.json"}); err != nil {
+		util.Fatal(err)
+	}
+	g.Config.Process.Capabilities = &rspec.LinuxCapabilities{
+		Bounding:  []string{"CAP_CHOWN", "CAP_KILL"},
+		Effective: []string{"CAP_CHOWN"},
+		Permitted: []string{"CAP_CHOWN"},
+	}
+	process := g.ExecProcess()
+	// runtimetest reads the exec'd process spec from the rootfs, which is
+	// the bundle directory.
+	data, err := json.Marshal(process)
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(r.BundleDir, "exec-process.json"), data, 0o644); err != nil {
+		util.Fatal(err)
+	}
+
+	r.SetID(uuid.NewString())
+	if err := r.Create(); err != nil {
+		util.Fatal(err)
+	}
+	if err := r.Start(); err != nil {
+		util.Fatal(err)
+	}
+	if err := util.WaitingForStatus(r, util.LifecycleStatusRunning, time.Second*10, time.Second); err != nil {
+		util.Fatal(err)
+	}
+
+	if err := r.Exec(process); err != nil {
+		util.Fatal(err)
+	}
+	stdout, _, err := r.ReadStandardStreams()
+	if err != nil {
+		util.Fatal(err)
+	}
+	os.Stdout.Write(stdout)
+
+	if err := r.Kill("KILL"); err != nil {
+		util.Fatal(err)
+	}
+	if err := util.WaitingForStatus(r, util.LifecycleStatusStopped, time.Second*10, time.Second); err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrunalp/fileutils"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	defer r.Clean()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetProcessArgs([]string{"sleep", "30"}); err != nil {
+		util.Fatal(err)
+	}
+	if err := r.SetConfig(g); err != nil {
+		util.Fatal(err)
+	}
+	if err := fileutils.CopyFile("runtimetest", filepath.Join(r.BundleDir, "runtimetest")); err != nil {
+		util.Fatal(err)
+	}
+
+	r.SetID(uuid.NewString())
+	if err := r.Create(); err != nil {
+		util.Fatal(err)
+	}
+	if err := r.Start(); err != nil {
+		util.Fatal(err)
+	}
+	if err := util.WaitingForStatus(r, util.LifecycleStatusRunning, time.Second*10, time.Second); err != nil {
+		util.Fatal(err)
+	}
+
+	// runtimetest compares its namespaces with those of the container
+	// process, and emits its own TAP report.
+	if err := g.SetProcessArgs([]string{"/runtimetest", "--path=/", "--exec"}); err != nil {
+		util.Fatal(err)
+	}
+	if err := r.Exec(g.ExecProcess()); err != nil {
+		util.Fatal(err)
+	}
+	stdout, _, err := r.ReadStandardStreams()
+	if err != nil {
+		util.Fatal(err)
+	}
+	os.Stdout.Write(stdout)
+
+	if err := r.Kill("KILL"); err != nil {
+		util.Fatal(err)
+	}
+	if err := util.WaitingForStatus(r, util.LifecycleStatusStopped, time.Second*10, time.Second); err != nil {
+		util.Fatal(err)
+	}
+}
@@ -65,7 +65,9 @@ func main() {
 					"sh", "-c", fmt.Sprintf("echo 'post-stop2 called' >> %s", output),
 				},
 			})
-			g.SetProcessArgs([]string{"true"})
+			if err := g.SetProcessArgs([]string{"true"}); err != nil {
+				return err
+			}
 			return r.SetConfig(g)
 		},
 		PreDelete: func(r *util.Runtime) error {
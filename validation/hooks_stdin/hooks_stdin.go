@@ -117,7 +117,9 @@ func main() {
 		},
 		Timeout: &timeout,
 	})
-	g.SetProcessArgs([]string{"true"})
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
 	config := util.LifecycleConfig{
 		BundleDir: bundleDir,
 		Config:    g,
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+const hostname = "hostname-uts"
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	if "linux" != runtime.GOOS {
+		t.Skip(1, "linux-specific namespace test")
+		return
+	}
+
+	// Without a UTS namespace of its own, setting the hostname would change
+	// the runtime's, so the config is invalid.
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		r.Clean()
+		util.Fatal(err)
+	}
+	if err := g.RemoveLinuxNamespace("uts"); err != nil {
+		r.Clean()
+		util.Fatal(err)
+	}
+	g.SetHostname(hostname)
+	if err := r.SetConfig(g); err != nil {
+		r.Clean()
+		util.Fatal(err)
+	}
+	r.SetID(uuid.NewString())
+	err = r.Create()
+	util.SpecErrorOK(t, err != nil, specerror.NewError(specerror.ValidValues, fmt.Errorf("create MUST generate an error for a hostname without a new UTS namespace"), rspecs.Version), err)
+	r.Clean()
+
+	// With the namespace, runtimetest checks the hostname was set.
+	g, err = util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.AddOrReplaceLinuxNamespace("uts", ""); err != nil {
+		util.Fatal(err)
+	}
+	g.SetHostname(hostname)
+	err = util.RuntimeInsideValidate(g, t, nil)
+	if err != nil {
+		t.Fail(err.Error())
+	}
+}
@@ -26,12 +26,16 @@ func main() {
 	if err != nil {
 		util.Fatal(err)
 	}
-	stoppedConfig.SetProcessArgs([]string{"true"})
+	if err := stoppedConfig.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
 	runningConfig, err := util.GetDefaultGenerator()
 	if err != nil {
 		util.Fatal(err)
 	}
-	runningConfig.SetProcessArgs([]string{"sleep", "30"})
+	if err := runningConfig.SetProcessArgs([]string{"sleep", "30"}); err != nil {
+		util.Fatal(err)
+	}
 	containerID := uuid.NewString()
 
 	cases := []struct {
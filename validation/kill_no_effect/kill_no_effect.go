@@ -28,7 +28,9 @@ func main() {
 	if err != nil {
 		util.Fatal(err)
 	}
-	g.SetProcessArgs([]string{"true"})
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
 
 	config := util.LifecycleConfig{
 		Config:    g,
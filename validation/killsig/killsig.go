@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,10 +15,18 @@ import (
 	"github.com/opencontainers/runtime-tools/validation/util"
 )
 
-var signals = []string{
-	"TERM",
-	"USR1",
-	"USR2",
+// signals pairs the signal passed to `kill` with the name the container's
+// shell traps it by. Runtimes are expected to accept the signal name with or
+// without the SIG prefix, and its number.
+var signals = []struct {
+	kill string
+	trap string
+}{
+	{"TERM", "TERM"},
+	{"USR1", "USR1"},
+	{"USR2", "USR2"},
+	{"SIGUSR1", "USR1"},
+	{strconv.Itoa(int(syscall.SIGUSR1)), "USR1"},
 }
 
 func main() {
@@ -36,7 +46,11 @@ func main() {
 	}
 	rootDir := filepath.Join(bundleDir, sigConfig.Config.Root.Path)
 	for _, signal := range signals {
-		sigConfig.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("trap 'touch /%s' %s; sleep 10 & wait $!", signal, signal)})
+		// The sentinel is named after the signal as given to kill, so a file
+		// left over from an earlier iteration cannot pass this one.
+		if err := sigConfig.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("trap 'touch /%s' %s; sleep 10 & wait $!", signal.kill, signal.trap)}); err != nil {
+			util.Fatal(err)
+		}
 		config := util.LifecycleConfig{
 			Config:    sigConfig,
 			BundleDir: bundleDir,
@@ -47,7 +61,7 @@ func main() {
 			},
 			PreDelete: func(r *util.Runtime) error {
 				util.WaitingForStatus(*r, util.LifecycleStatusRunning, time.Second*5, time.Second*1)
-				err = r.Kill(signal)
+				err = r.Kill(signal.kill)
 				// wait before the container been deleted
 				util.WaitingForStatus(*r, util.LifecycleStatusStopped, time.Second*5, time.Second*1)
 				return err
@@ -57,7 +71,7 @@ func main() {
 		if err != nil {
 			util.SpecErrorOK(t, false, specerror.NewError(specerror.KillSignalImplement, fmt.Errorf("`kill` operation MUST send the specified signal to the container process"), rspecs.Version), err)
 		} else {
-			_, err = os.Stat(filepath.Join(rootDir, signal))
+			_, err = os.Stat(filepath.Join(rootDir, signal.kill))
 			util.SpecErrorOK(t, err == nil, specerror.NewError(specerror.KillSignalImplement, fmt.Errorf("`kill` operation MUST send the specified signal to the container process"), rspecs.Version), err)
 		}
 	}
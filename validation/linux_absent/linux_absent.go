@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// The linux block is OPTIONAL, so a config with only process and root is
+// valid. Without it the runtime joins no namespaces and applies no
+// Linux-specific settings; it may run the container as is, or refuse it
+// with an error, but it must not crash on the missing block.
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	defer r.Clean()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// Mounts, and a hostname, would be applied to the host without the
+	// namespaces in the linux block.
+	g.Config.Linux = nil
+	g.Config.Mounts = nil
+	g.Config.Hostname = ""
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
+	if err := r.SetConfig(g); err != nil {
+		util.Fatal(err)
+	}
+
+	r.SetID(uuid.NewString())
+	err = r.Create()
+	if err != nil {
+		crashed := false
+		if e, ok := err.(*exec.ExitError); ok {
+			crashed = bytes.Contains(e.Stderr, []byte("panic:")) || !e.Exited()
+		}
+		util.ErrorOK(t, !crashed, "create runs a config without linux, or generates an error for it, without crashing", err)
+		return
+	}
+
+	err = r.Start()
+	if err == nil {
+		err = util.WaitingForStatus(r, util.LifecycleStatusStopped, time.Second*10, time.Second)
+	}
+	util.ErrorOK(t, err == nil, "a created container without linux runs", err)
+}
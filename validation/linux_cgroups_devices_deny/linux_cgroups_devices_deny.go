@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	mode := os.FileMode(0o666)
+	uid := uint32(0)
+	gid := uint32(0)
+	for _, device := range []rspec.LinuxDevice{
+		{Path: "/dev/test-allowed", Type: "c", Major: 10, Minor: 666, FileMode: &mode, UID: &uid, GID: &gid},
+		{Path: "/dev/test-denied", Type: "c", Major: 10, Minor: 667, FileMode: &mode, UID: &uid, GID: &gid},
+	} {
+		g.AddDevice(device)
+	}
+
+	// The default config denies all devices, so /dev/test-denied fails to
+	// open with EPERM. Neither number has a driver, so /dev/test-allowed
+	// gets past the device cgroup and fails with ENXIO instead. Numbers
+	// outside the default devices are used, since runtimes always allow
+	// those.
+	major, minor := int64(10), int64(666)
+	g.AddLinuxResourcesDevice(true, "c", &major, &minor, "rwm")
+
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+const limit = 32 << 20
+
+// memoryControllerEnabled reports whether the host kernel has the memory
+// cgroup controller enabled.
+func memoryControllerEnabled() bool {
+	f, err := os.Open("/proc/cgroups")
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// subsys_name hierarchy num_cgroups enabled
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 4 && fields[0] == "memory" {
+			return fields[3] == "1"
+		}
+	}
+	return false
+}
+
+func main() {
+	if !memoryControllerEnabled() {
+		util.Skip("the memory cgroup controller is not available", nil)
+		os.Exit(0)
+	}
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// runtimetest allocates well beyond the limit in a child and expects it
+	// to fail. Swap is capped at the limit so it cannot absorb the excess.
+	g.SetLinuxResourcesMemoryLimit(limit)
+	g.SetLinuxResourcesMemorySwap(limit)
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
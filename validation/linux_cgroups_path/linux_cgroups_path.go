@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+
+	"github.com/opencontainers/runtime-tools/cgroups"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	if _, err := os.Stat("/proc/self/cgroup"); err != nil {
+		util.Skip("cgroups are not available on this host", map[string]string{"error": err.Error()})
+		os.Exit(0)
+	}
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	g.SetLinuxCgroupsPath(cgroups.AbsCgroupPath)
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
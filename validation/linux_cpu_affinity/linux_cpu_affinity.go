@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	if runtime.NumCPU() < 2 {
+		util.Skip("pinning to a single CPU cannot be told apart on a single-CPU host", nil)
+		os.Exit(0)
+	}
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// runtimetest compares sched_getaffinity with the configured cpus.
+	g.SetLinuxResourcesCPUCpus("0")
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	if runtime.NumCPU() < 3 {
+		util.Skip("a cpuset of two CPUs cannot be told apart from all of them on fewer than three", nil)
+		os.Exit(0)
+	}
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// runtimetest compares the affinity count with the size of the cpuset.
+	g.SetLinuxResourcesCPUCpus("1,0")
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
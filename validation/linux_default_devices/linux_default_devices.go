@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// The default devices must be there whatever linux.devices lists, so
+	// the list is emptied. runtimetest checks each default device's type and
+	// major:minor.
+	g.ClearLinuxDevices()
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// runtimetest checks the mode, owner and group of every device in
+	// linux.devices. The owners differ from the container user and from
+	// each other, and 0666 would lose bits to a umask the runtime forgot
+	// to clear while creating the node.
+	for _, d := range []struct {
+		path     string
+		mode     os.FileMode
+		uid, gid uint32
+	}{
+		{"/dev/test-0600", 0o600, 1000, 1001},
+		{"/dev/test-0666", 0o666, 1002, 1003},
+	} {
+		mode, uid, gid := d.mode, d.uid, d.gid
+		g.AddDevice(rspecs.LinuxDevice{
+			Path:     d.path,
+			Type:     "c",
+			Major:    1,
+			Minor:    3,
+			FileMode: &mode,
+			UID:      &uid,
+			GID:      &gid,
+		})
+	}
+
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
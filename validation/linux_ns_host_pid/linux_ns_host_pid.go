@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// Without a PID namespace the container inherits the runtime's, which
+	// is this process's, so runtimetest should find this process in /proc.
+	if err := g.SetLinuxNamespacesProfile("host-pid"); err != nil {
+		util.Fatal(err)
+	}
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.host-pid", strconv.Itoa(os.Getpid()))
+
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
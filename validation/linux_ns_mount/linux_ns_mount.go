@@ -0,0 +1,31 @@
+package main
+
+import (
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// runtimetest checks that only the root filesystem and the mounts
+	// requested by the config are visible in a fresh mount namespace.
+	if err := g.AddOrReplaceLinuxNamespace("mount", ""); err != nil {
+		util.Fatal(err)
+	}
+	if err := g.AddMount(rspec.Mount{
+		Destination: "/tmp",
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+		Options:     []string{"nosuid", "nodev"},
+	}); err != nil {
+		util.Fatal(err)
+	}
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.mount-isolation", "true")
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
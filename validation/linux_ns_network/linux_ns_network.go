@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// A fresh network namespace only has a loopback interface, which
+	// runtimetest checks for.
+	if err := g.AddOrReplaceLinuxNamespace("network", ""); err != nil {
+		util.Fatal(err)
+	}
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// Where the runtime puts an init of its own in the new PID namespace,
+	// runtimetest orphans a process to it and checks that it gets reaped.
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.reap-orphans", "true")
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+	"golang.org/x/sys/unix"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// CAP_SYS_ADMIN permits sethostname(2) in the container's UTS
+	// namespace, so only the seccomp rule stops it. The rule returns an
+	// errno sethostname cannot return by itself, which tells it apart
+	// from the EPERM of a missing capability.
+	if err := g.AddProcessCapability("CAP_SYS_ADMIN"); err != nil {
+		util.Fatal(err)
+	}
+	if err := g.AddOrReplaceLinuxNamespace(string(rspecs.UTSNamespace), ""); err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetDefaultSeccompAction("allow"); err != nil {
+		util.Fatal(err)
+	}
+	errno := uint(unix.EXDEV)
+	if err := g.AddLinuxSeccompSyscall(rspecs.LinuxSyscall{
+		Names:    []string{"sethostname"},
+		Action:   rspecs.ActErrno,
+		ErrnoRet: &errno,
+	}); err != nil {
+		util.Fatal(err)
+	}
+
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	tap "github.com/mndrix/tap-go"
+	"github.com/opencontainers/runtime-tools/generate/seccomp"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// seccompSupported reports whether the kernel exposes seccomp state for
+// processes, which is only the case when it was built with seccomp support.
+func seccompSupported() bool {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "Seccomp:") {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	if !seccompSupported() {
+		util.Skip("seccomp is not supported on this host", nil)
+		os.Exit(0)
+	}
+
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetDefaultSeccompAction("allow"); err != nil {
+		util.Fatal(err)
+	}
+	// chmod(2) is implemented on top of fchmodat(2) on some architectures,
+	// so block both to make the probe inside the container reliable.
+	for _, name := range []string{"chmod", "fchmodat"} {
+		if err := g.SetSyscallAction(seccomp.SyscallOpts{
+			Action:  "errno",
+			Syscall: name,
+		}); err != nil {
+			util.Fatal(err)
+		}
+	}
+
+	g.AddAnnotation("TestName", "chmod is blocked with errno by seccomp")
+	err = util.RuntimeInsideValidate(g, t, nil)
+	if err != nil {
+		t.Fail(err.Error())
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+const (
+	sysctlKey   = "net.ipv4.ip_forward"
+	sysctlValue = "1"
+)
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	// The sysctl is for the container, so without a network namespace of its
+	// own there is nowhere to set a net.* key but the host, and the runtime
+	// should refuse.
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		r.Clean()
+		util.Fatal(err)
+	}
+	if err := g.RemoveLinuxNamespace("network"); err != nil {
+		r.Clean()
+		util.Fatal(err)
+	}
+	g.AddLinuxSysctl(sysctlKey, sysctlValue)
+	if err := r.SetConfig(g); err != nil {
+		r.Clean()
+		util.Fatal(err)
+	}
+	r.SetID(uuid.NewString())
+	err = r.Create()
+	util.ErrorOK(t, err != nil, fmt.Sprintf("create fails for a %s sysctl without a network namespace", sysctlKey), err)
+	r.Clean()
+
+	// With the namespace, runtimetest checks the value was applied.
+	g, err = util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.AddOrReplaceLinuxNamespace("network", ""); err != nil {
+		util.Fatal(err)
+	}
+	g.AddLinuxSysctl(sysctlKey, sysctlValue)
+	err = util.RuntimeInsideValidate(g, t, nil)
+	if err != nil {
+		util.ErrorOK(t, false, fmt.Sprintf("create succeeds for a %s sysctl with a network namespace", sysctlKey), err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tap "github.com/mndrix/tap-go"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	"github.com/opencontainers/runtime-tools/generate/seccomp"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// apparmorProfile is the profile Docker loads for its containers, which a
+// host running AppArmor is likely to have.
+const apparmorProfile = "docker-default"
+
+// apparmorProfileLoaded reports whether the host kernel has AppArmor enabled
+// with profile loaded.
+func apparmorProfileLoaded(profile string) bool {
+	data, err := os.ReadFile("/sys/kernel/security/apparmor/profiles")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		// Each line is "name (mode)".
+		if name, _, _ := strings.Cut(line, " ("); name == profile {
+			return true
+		}
+	}
+	return false
+}
+
+func userNSGenerator() (*generate.Generator, error) {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		return nil, err
+	}
+	g.AddOrReplaceLinuxNamespace("user", "")
+	g.AddLinuxUIDMapping(uint32(1000), uint32(0), uint32(2000))
+	g.AddLinuxGIDMapping(uint32(1000), uint32(0), uint32(3000))
+	return g, nil
+}
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	// Installing a seccomp filter needs no privilege once no_new_privs is
+	// set, so a runtime has no excuse to drop it in a user namespace.
+	// runtimetest checks that fchmodat is really blocked.
+	g, err := userNSGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	g.SetDefaultSeccompAction("allow")
+	if err := g.SetSyscallAction(seccomp.SyscallOpts{
+		Action:  "errno",
+		Syscall: "fchmodat",
+	}); err != nil {
+		util.Fatal(err)
+	}
+	err = util.RuntimeInsideValidate(g, t, nil)
+	if err != nil {
+		util.SpecErrorOK(t, false, specerror.NewError(specerror.SeccSyscallsErrnoRet, fmt.Errorf("seccomp filter is applied inside a user namespace"), rspec.Version), err)
+	}
+
+	if !apparmorProfileLoaded(apparmorProfile) {
+		t.Skip(1, fmt.Sprintf("AppArmor profile %s is not loaded on the host", apparmorProfile))
+		return
+	}
+
+	// Changing to an AppArmor profile can need privileges the runtime lacks
+	// when rootless. A runtime which cannot do so has to refuse the config
+	// rather than run the process unconfined, so a refusal is reported as a
+	// passing result.
+	g, err = userNSGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	g.SetProcessApparmorProfile(apparmorProfile)
+	g.AddAnnotation("TestName", "apparmorProfile is applied inside a user namespace")
+	err = util.RuntimeInsideValidate(g, t, nil)
+	if err != nil {
+		util.SpecErrorOK(t, true, specerror.NewError(specerror.PropApplyFailGenError, fmt.Errorf("runtime generates an error when it cannot apply apparmorProfile inside a user namespace"), rspec.Version), err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	if os.Geteuid() != 0 {
+		util.Skip("files can only be given other host owners as root", nil)
+		os.Exit(0)
+	}
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// Host root stays container root, so the rootfs owned by it is too,
+	// while host IDs from 100000 are container IDs from 1.
+	g.AddOrReplaceLinuxNamespace("user", "")
+	g.AddLinuxUIDMapping(0, 0, 1)
+	g.AddLinuxUIDMapping(100000, 1, 65535)
+	g.AddLinuxGIDMapping(0, 0, 1)
+	g.AddLinuxGIDMapping(100000, 1, 65535)
+
+	err = util.RuntimeInsideValidate(g, nil, func(path string) error {
+		mapped := filepath.Join(path, "mapped-owner")
+		if err := os.WriteFile(mapped, nil, 0o644); err != nil {
+			return err
+		}
+		if err := os.Lchown(mapped, 100041, 100041); err != nil {
+			return err
+		}
+
+		// runtimetest checks the owners it sees against these.
+		owners, err := json.Marshal(map[string]map[string]uint32{
+			"/":             {"uid": 0, "gid": 0},
+			"/bin/busybox":  {"uid": 0, "gid": 0},
+			"/mapped-owner": {"uid": 42, "gid": 42},
+		})
+		if err != nil {
+			return err
+		}
+		g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.owners", string(owners))
+		return nil
+	})
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -43,7 +43,9 @@ func main() {
 	if err != nil {
 		util.Fatal(err)
 	}
-	basicConfig.SetProcessArgs([]string{"true"})
+	if err := basicConfig.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
 	annotationConfig, err := util.GetDefaultGenerator()
 	if err != nil {
 		util.Fatal(err)
@@ -83,7 +83,9 @@ func main() {
 	for _, m := range mounts {
 		m.Options = append(defaultOptions, m.Options...)
 
-		g.AddMount(m)
+		if err := g.AddMount(m); err != nil {
+			util.Fatal(err)
+		}
 	}
 	err = util.RuntimeInsideValidate(g, nil, nil)
 	if err != nil {
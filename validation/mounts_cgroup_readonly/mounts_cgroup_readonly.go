@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/mndrix/tap-go"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// testCgroupMount runs runtimetest with a cgroup mount on /sys/fs/cgroup,
+// which it expects to be read-only unless the options make it writable.
+func testCgroupMount(t *tap.T, privileged bool) error {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		return err
+	}
+
+	mode := "ro"
+	if privileged {
+		mode = "rw"
+		g.SetupPrivileged(true)
+	}
+	g.RemoveMount("/sys/fs/cgroup")
+	mnt := rspec.Mount{
+		Destination: "/sys/fs/cgroup",
+		Type:        "cgroup",
+		Source:      "cgroup",
+		Options:     []string{"nosuid", "noexec", "nodev", "relatime", mode},
+	}
+	if generate.IsCgroup2UnifiedMode() {
+		mnt.Type, mnt.Source = "cgroup2", "cgroup2"
+	}
+	if err := g.AddMount(mnt); err != nil {
+		return err
+	}
+
+	g.AddAnnotation("TestName", "check "+mode+" cgroup mount")
+	return util.RuntimeInsideValidate(g, t, nil)
+}
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	// A privileged config deliberately makes the cgroup mount writable,
+	// which runtimetest then skips.
+	for _, privileged := range []bool{false, true} {
+		if err := testCgroupMount(t, privileged); err != nil {
+			t.Fail(err.Error())
+		}
+	}
+}
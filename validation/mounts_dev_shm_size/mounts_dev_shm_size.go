@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// runtimetest compares the size of /dev/shm with statfs.
+	if err := g.SetDevShmSize(128 << 20); err != nil {
+		util.Fatal(err)
+	}
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.tmpfs-size", "/dev/shm")
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// A bind mount only gets its flags from a separate remount, which must
+	// keep all of them, so runtimetest checks mountinfo for every flag on
+	// both a tmpfs and a bind mount.
+	flags := []string{"nosuid", "nodev", "noexec", "ro"}
+	err = util.RuntimeInsideValidate(g, nil, func(path string) error {
+		source := filepath.Join(path, "flags-source")
+		if err := os.MkdirAll(source, 0o755); err != nil {
+			return err
+		}
+		for _, mnt := range []rspec.Mount{
+			{
+				Destination: "/mnt/flags-tmpfs",
+				Type:        "tmpfs",
+				Source:      "tmpfs",
+				Options:     flags,
+			},
+			{
+				Destination: "/mnt/flags-bind",
+				Source:      source,
+				Options:     append([]string{"bind"}, flags...),
+			},
+		} {
+			if err := g.AddMount(mnt); err != nil {
+				return err
+			}
+		}
+		g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.mount-flags", "/mnt/flags-tmpfs,/mnt/flags-bind")
+		return nil
+	})
+	if err != nil {
+		util.Fatal(err)
+	}
+}
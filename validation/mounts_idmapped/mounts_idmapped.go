@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	mappings := []rspec.LinuxIDMapping{
+		{
+			ContainerID: 0,
+			HostID:      1000,
+			Size:        1,
+		},
+	}
+
+	err = util.RuntimeInsideValidate(g, nil, func(path string) error {
+		source := filepath.Join(path, "idmapped-source")
+		if err := os.MkdirAll(source, 0o755); err != nil {
+			return err
+		}
+		return g.AddMountWithIDMapping(rspec.Mount{
+			Destination: "/mnt/idmapped",
+			Source:      source,
+			Options:     []string{"bind"},
+		}, mappings, mappings)
+	})
+	if err != nil {
+		util.Fatal(err)
+	}
+}
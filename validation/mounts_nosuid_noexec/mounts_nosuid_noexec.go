@@ -0,0 +1,38 @@
+package main
+
+import (
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// runtimetest checks the nosuid flag on the first mount, and tries to
+	// run a copy of itself from the second.
+	for _, mnt := range []rspec.Mount{
+		{
+			Destination: "/tmp/nosuid",
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Options:     []string{"nosuid"},
+		},
+		{
+			Destination: "/tmp/noexec",
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Options:     []string{"noexec"},
+		},
+	} {
+		if err := g.AddMount(mnt); err != nil {
+			util.Fatal(err)
+		}
+	}
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.nosuid-noexec", "/tmp/nosuid,/tmp/noexec")
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/mndrix/tap-go"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// testProcMount runs runtimetest with a /proc mount using options, which it
+// expects to find on the proc mount in /proc/self/mountinfo.
+func testProcMount(t *tap.T, options []string) error {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		return err
+	}
+
+	g.RemoveMount("/proc")
+	if err := g.AddMount(rspec.Mount{
+		Destination: "/proc",
+		Type:        "proc",
+		Source:      "proc",
+		Options:     options,
+	}); err != nil {
+		return err
+	}
+
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.proc-mount", "true")
+	g.AddAnnotation("TestName", "check /proc mount with "+strings.Join(options, ","))
+	return util.RuntimeInsideValidate(g, t, nil)
+}
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	// Only the flags asked for are expected, so a relaxed /proc mount is
+	// checked for nosuid alone.
+	for _, options := range [][]string{
+		{"nosuid", "noexec", "nodev"},
+		{"nosuid"},
+	} {
+		if err := testProcMount(t, options); err != nil {
+			t.Fail(err.Error())
+		}
+	}
+}
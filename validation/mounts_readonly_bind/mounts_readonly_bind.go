@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// A bind mount with "ro" is only read-only if the runtime remounts it,
+	// so runtimetest checks that writes on it fail with EROFS.
+	err = util.RuntimeInsideValidate(g, nil, func(path string) error {
+		source := filepath.Join(path, "readonly-source")
+		if err := os.MkdirAll(source, 0o755); err != nil {
+			return err
+		}
+		return g.AddMount(rspec.Mount{
+			Destination: "/mnt/readonly",
+			Source:      source,
+			Options:     []string{"bind", "ro"},
+		})
+	})
+	if err != nil {
+		util.Fatal(err)
+	}
+}
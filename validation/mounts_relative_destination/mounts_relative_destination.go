@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	defer r.Clean()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// AddMount rejects a relative destination, so the mount is appended
+	// directly. Since v1.1.0 the spec deprecates relative destinations but
+	// resolves them against "/", so a runtime may accept or refuse it.
+	g.Config.Mounts = append(g.Config.Mounts, rspecs.Mount{
+		Destination: "relative/tmp",
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+	})
+	if err := r.SetConfig(g); err != nil {
+		util.Fatal(err)
+	}
+
+	r.SetID(uuid.NewString())
+	err = r.Create()
+	if err != nil {
+		t.Skip(1, "create rejects a relative mount destination")
+		_ = t.YAML(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+	t.Pass("create accepts a relative mount destination")
+}
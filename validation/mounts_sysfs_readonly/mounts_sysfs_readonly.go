@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/mndrix/tap-go"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// testSysfsMount runs runtimetest with a sysfs mount on /sys, which it
+// expects to reject writes with EROFS unless the options make it writable.
+func testSysfsMount(t *tap.T, privileged bool) error {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		return err
+	}
+
+	mode := "ro"
+	if privileged {
+		mode = "rw"
+		g.SetupPrivileged(true)
+	}
+	g.RemoveMount("/sys")
+	if err := g.AddMount(rspec.Mount{
+		Destination: "/sys",
+		Type:        "sysfs",
+		Source:      "sysfs",
+		Options:     []string{"nosuid", "noexec", "nodev", mode},
+	}); err != nil {
+		return err
+	}
+
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.sysfs-readonly", "true")
+	g.AddAnnotation("TestName", "check "+mode+" sysfs mount")
+	return util.RuntimeInsideValidate(g, t, nil)
+}
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	// A privileged config deliberately makes /sys writable, which
+	// runtimetest then skips.
+	for _, privileged := range []bool{false, true} {
+		if err := testSysfsMount(t, privileged); err != nil {
+			t.Fail(err.Error())
+		}
+	}
+}
@@ -40,7 +40,9 @@ func main() {
 		},
 	}
 	g.AddPostStartHook(poststartOK)
-	g.SetProcessArgs([]string{"true"})
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
 	config := util.LifecycleConfig{
 		Config:    g,
 		BundleDir: bundleDir,
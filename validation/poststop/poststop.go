@@ -36,7 +36,9 @@ func main() {
 					"sh", "-c", fmt.Sprintf("echo 'post-stop called' >> %s", output),
 				},
 			})
-			g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("echo 'process called' >> %s", "/output")})
+			if err := g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("echo 'process called' >> %s", "/output")}); err != nil {
+				return err
+			}
 			return r.SetConfig(g)
 		},
 		PostCreate: func(r *util.Runtime) error {
@@ -77,8 +79,7 @@ func main() {
 			case "post-stop called\n":
 				return specerror.NewError(specerror.ProcImplement, fmt.Errorf("The runtime MUST run the user-specified program, as specified by `process`"), rspec.Version)
 			case "process called\n":
-				fmt.Fprintln(os.Stderr, "WARNING: The poststop hook invoke fails")
-				return nil
+				return specerror.NewError(specerror.PoststopHooksInvoke, fmt.Errorf("The poststop hooks MUST be invoked by the runtime"), rspec.Version)
 			case "post-stop called\nprocess called\n":
 				return errors.New("The Post-stop should called after the user-specified program command is executed")
 			case "process called\npost-stop called\n":
@@ -90,6 +91,7 @@ func main() {
 	}
 
 	err := util.RuntimeLifecycleValidate(config)
+	t.Ok(err == nil, "post-stop hooks are called after the container is deleted")
 	if err != nil {
 		diagnostic := map[string]string{
 			"error": err.Error(),
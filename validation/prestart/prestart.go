@@ -35,7 +35,9 @@ func main() {
 					"sh", "-c", fmt.Sprintf("echo 'pre-start called' >> %s", output),
 				},
 			})
-			g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("echo 'process called' >> %s", "/output")})
+			if err := g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("echo 'process called' >> %s", "/output")}); err != nil {
+				return err
+			}
 			return r.SetConfig(g)
 		},
 		PostCreate: func(r *util.Runtime) error {
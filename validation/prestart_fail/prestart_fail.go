@@ -31,7 +31,9 @@ func main() {
 		Args: []string{"false"},
 	}
 	g.AddPreStartHook(prestart)
-	g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("touch %s", "/output")})
+	if err := g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("touch %s", "/output")}); err != nil {
+		util.Fatal(err)
+	}
 	containerID := uuid.NewString()
 
 	config := util.LifecycleConfig{
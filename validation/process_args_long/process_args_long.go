@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// argCount arguments of a dozen bytes each stay far below ARG_MAX, which is
+// at least 128KiB on Linux.
+const argCount = 1000
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// runtimetest ignores its positional arguments, but checks that
+	// /proc/self/cmdline matches process.args.
+	args := []string{"/runtimetest", "--path=/"}
+	for i := 0; i < argCount; i++ {
+		args = append(args, fmt.Sprintf("argument-%04d", i))
+	}
+	if err := g.SetProcessArgs(args); err != nil {
+		util.Fatal(err)
+	}
+
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
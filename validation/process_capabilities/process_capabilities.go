@@ -17,6 +17,8 @@ func main() {
 	if err != nil {
 		util.Fatal(err)
 	}
+	// Besides comparing the capability sets, runtimetest checks that the
+	// granted CAP_SYS_ADMIN is usable by mounting a tmpfs.
 	g.SetupPrivileged(true)
 	err = util.RuntimeInsideValidate(g, nil, nil)
 	if err != nil {
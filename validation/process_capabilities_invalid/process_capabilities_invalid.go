@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+const bogusCapability = "CAP_BOGUS"
+
+// createWithBogusCapability creates a container whose capability set,
+// selected by set, includes bogusCapability, and checks that create fails.
+func createWithBogusCapability(t *tap.T, name string, set func(*rspecs.LinuxCapabilities) *[]string) error {
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		return err
+	}
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		return err
+	}
+	defer r.Clean()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		return err
+	}
+	// This config is deliberately invalid: the AddProcessCapability*
+	// methods reject unknown names, so the capability is appended
+	// directly to check that the runtime enforces the rule too.
+	caps := set(g.Config.Process.Capabilities)
+	*caps = append(*caps, bogusCapability)
+	if err := r.SetConfig(g); err != nil {
+		return err
+	}
+
+	r.SetID(uuid.NewString())
+	err = r.Create()
+	util.SpecErrorOK(t, err != nil, specerror.NewError(specerror.LinuxProcCapError, fmt.Errorf("create MUST generate an error for the unknown capability %s in process.capabilities.%s", bogusCapability, name), rspecs.Version), err)
+	return nil
+}
+
+func main() {
+	if "linux" != runtime.GOOS {
+		util.Skip("linux-specific process.capabilities test", map[string]string{"OS": runtime.GOOS})
+		os.Exit(0)
+	}
+
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	for _, c := range []struct {
+		name string
+		set  func(*rspecs.LinuxCapabilities) *[]string
+	}{
+		{"bounding", func(caps *rspecs.LinuxCapabilities) *[]string { return &caps.Bounding }},
+		{"effective", func(caps *rspecs.LinuxCapabilities) *[]string { return &caps.Effective }},
+		{"inheritable", func(caps *rspecs.LinuxCapabilities) *[]string { return &caps.Inheritable }},
+		{"permitted", func(caps *rspecs.LinuxCapabilities) *[]string { return &caps.Permitted }},
+		{"ambient", func(caps *rspecs.LinuxCapabilities) *[]string { return &caps.Ambient }},
+	} {
+		if err := createWithBogusCapability(t, c.name, c.set); err != nil {
+			util.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrunalp/fileutils"
+	"github.com/opencontainers/runtime-tools/validation/util"
+	"golang.org/x/sys/unix"
+)
+
+// receiveConsole accepts one connection on l and returns the pseudoterminal
+// master the runtime passes over it.
+func receiveConsole(l *net.UnixListener) (*os.File, error) {
+	conn, err := l.AcceptUnix()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 4096)
+	oob := make([]byte, unix.CmsgSpace(4))
+	_, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, errors.New("console socket message has no control message")
+	}
+	fds, err := unix.ParseUnixRights(&msgs[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(fds) == 0 {
+		return nil, errors.New("console socket message has no file descriptor")
+	}
+	return os.NewFile(uintptr(fds[0]), "console"), nil
+}
+
+func main() {
+	// The harness has no terminal of its own to hand out, so it depends
+	// on the host being able to allocate pseudoterminals.
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		util.Skip("cannot allocate a pseudoterminal", map[string]string{"error": err.Error()})
+		os.Exit(0)
+	}
+	ptmx.Close()
+
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	defer r.Clean()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	g.SetProcessTerminal(true)
+	g.SetProcessConsoleSize(123, 45)
+	if err := r.SetConfig(g); err != nil {
+		util.Fatal(err)
+	}
+	if err := fileutils.CopyFile("runtimetest", filepath.Join(r.BundleDir, "runtimetest")); err != nil {
+		util.Fatal(err)
+	}
+
+	r.ConsoleSocket = filepath.Join(r.BundleDir, "console.sock")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: r.ConsoleSocket, Net: "unix"})
+	if err != nil {
+		util.Fatal(err)
+	}
+	defer l.Close()
+	type console struct {
+		f   *os.File
+		err error
+	}
+	consoles := make(chan console, 1)
+	go func() {
+		f, err := receiveConsole(l)
+		consoles <- console{f, err}
+	}()
+
+	r.SetID(uuid.NewString())
+	if err := r.Create(); err != nil {
+		util.Fatal(err)
+	}
+	var master *os.File
+	select {
+	case c := <-consoles:
+		if c.err != nil {
+			util.Fatal(c.err)
+		}
+		master = c.f
+	case <-time.After(10 * time.Second):
+		util.Fatal(errors.New("the runtime did not send the pseudoterminal master"))
+	}
+	defer master.Close()
+
+	// Reading the master fails with EIO once every slave is closed, that
+	// is once runtimetest exits.
+	var output bytes.Buffer
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(&output, master)
+		if errors.Is(err, syscall.EIO) {
+			err = nil
+		}
+		copied <- err
+	}()
+
+	if err := r.Start(); err != nil {
+		util.Fatal(err)
+	}
+	if err := util.WaitingForStatus(r, util.LifecycleStatusStopped, time.Second*10, time.Second); err != nil {
+		util.Fatal(err)
+	}
+	select {
+	case err := <-copied:
+		if err != nil {
+			util.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		util.Fatal(fmt.Errorf("the terminal was not closed after the container stopped"))
+	}
+
+	// The terminal translates newlines in runtimetest's TAP output.
+	os.Stdout.Write(bytes.ReplaceAll(output.Bytes(), []byte("\r\n"), []byte("\n")))
+}
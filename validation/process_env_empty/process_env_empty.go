@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// The runtime is run with this variable, so it leaks into the container
+	// if the runtime passes its own environment through; runtimetest flags
+	// anything beyond what a runtime may provide itself.
+	os.Setenv("RUNTIME_TOOLS_HOST_ONLY", "leaked")
+	g.ClearProcessEnv()
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// AddProcessEnv replaces existing keys, so the duplicates are appended
+	// directly. runtimetest expects getenv to return the last value.
+	g.Config.Process.Env = append(g.Config.Process.Env,
+		"RUNTIMETEST_DUP=first",
+		"RUNTIMETEST_OTHER=value=with=equals",
+		"RUNTIMETEST_DUP=second",
+	)
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+const exitStatus = 42
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+	defer os.RemoveAll(bundleDir)
+
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		util.Fatal(err)
+	}
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("exit %d", exitStatus)}); err != nil {
+		util.Fatal(err)
+	}
+	err = r.SetConfig(g)
+	if err != nil {
+		util.Fatal(err)
+	}
+	r.SetID(uuid.NewString())
+
+	err = r.Create()
+	if err != nil {
+		t.Fail(err.Error())
+		return
+	}
+	defer r.Clean()
+
+	err = r.Start()
+	util.SpecErrorOK(t, err == nil, specerror.NewError(specerror.StartProcImplement, fmt.Errorf("`start` operation MUST run the user-specified program as specified by `process`"), rspecs.Version), err)
+	if err != nil {
+		return
+	}
+
+	err = util.WaitingForStatus(r, util.LifecycleStatusStopped, time.Second*10, time.Second*1)
+	util.SpecErrorOK(t, err == nil, specerror.NewError(specerror.QueryStateImplement, fmt.Errorf("this operation MUST return the state of a container as specified in the State section"), rspecs.Version), err)
+	if err != nil {
+		return
+	}
+
+	actual, field, err := r.ExitStatus()
+	if err != nil {
+		t.Fail(err.Error())
+		return
+	}
+	if field == "" {
+		t.Skip(1, "the runtime state does not report the process exit status")
+		return
+	}
+	t.Ok(actual == exitStatus, fmt.Sprintf("state reports the process exit status in %q", field))
+	_ = t.YAML(map[string]interface{}{
+		"expected": exitStatus,
+		"actual":   actual,
+	})
+}
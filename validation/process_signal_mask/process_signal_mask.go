@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// Ignored signals are inherited across fork and exec, so the runtime
+	// starts with SIGHUP and SIGINT ignored, and has to reset them for the
+	// container process. runtimetest reads SigBlk and SigIgn from
+	// /proc/self/status.
+	signal.Ignore(syscall.SIGHUP, syscall.SIGINT)
+	g.AddAnnotation("com.github.opencontainers.runtime-tools.runtimetest.signals", "true")
+
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
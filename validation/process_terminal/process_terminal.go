@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	// runtimetest checks both ways, but RuntimeInsideValidate reads the
+	// container's output through pipes. terminal=true is driven through a
+	// console socket by process_console_size.
+	g.SetProcessTerminal(false)
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
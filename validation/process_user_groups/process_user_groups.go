@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// The primary group is distinct from, and numbered between, the
+	// additional groups, so taking it from the wrong list shows up.
+	g.SetProcessUID(10)
+	g.SetProcessGID(20)
+	for _, gid := range []uint32{5, 30, 40} {
+		g.AddProcessAdditionalGid(gid)
+	}
+
+	err = util.RuntimeInsideValidate(g, nil, nil)
+	if err != nil {
+		util.Fatal(err)
+	}
+}
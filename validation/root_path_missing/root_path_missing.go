@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	cases := []struct {
+		description string
+		rootPath    string
+		// prepare sets up rootPath inside the bundle.
+		prepare func(path string) error
+	}{
+		{
+			"a missing root path",
+			"missing-rootfs",
+			func(path string) error { return nil },
+		},
+		{
+			"a root path which is a file",
+			"rootfs-file",
+			func(path string) error { return os.WriteFile(path, nil, 0o644) },
+		},
+	}
+
+	for _, c := range cases {
+		bundleDir, err := util.PrepareBundle()
+		if err != nil {
+			util.Fatal(err)
+		}
+
+		r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+		if err != nil {
+			os.RemoveAll(bundleDir)
+			util.Fatal(err)
+		}
+
+		g, err := util.GetDefaultGenerator()
+		if err != nil {
+			r.Clean()
+			util.Fatal(err)
+		}
+		g.SetRootPath(c.rootPath)
+		if err := c.prepare(filepath.Join(bundleDir, c.rootPath)); err != nil {
+			r.Clean()
+			util.Fatal(err)
+		}
+		if err := r.SetConfig(g); err != nil {
+			r.Clean()
+			util.Fatal(err)
+		}
+
+		r.SetID(uuid.NewString())
+		err = r.Create()
+		util.SpecErrorOK(t, err != nil, specerror.NewError(specerror.RootPathExist, fmt.Errorf("create MUST generate an error for %s", c.description), rspecs.Version), err)
+		r.Clean()
+	}
+}
@@ -34,7 +34,9 @@ func main() {
 	if err != nil {
 		util.Fatal(err)
 	}
-	g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("echo 'process called' >> %s", "/output")})
+	if err := g.SetProcessArgs([]string{"sh", "-c", fmt.Sprintf("echo 'process called' >> %s", "/output")}); err != nil {
+		util.Fatal(err)
+	}
 	err = r.SetConfig(g)
 	if err != nil {
 		util.Fatal(err)
@@ -21,7 +21,9 @@ func main() {
 	if err != nil {
 		util.Fatal(err)
 	}
-	g.SetProcessArgs([]string{"true"})
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
 	containerID := uuid.NewString()
 
 	cases := []struct {
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		util.Fatal(err)
+	}
+	annotations := map[string]string{
+		"com.example.validation.one":   "1",
+		"com.example.validation.two":   "second value",
+		"com.example.validation.empty": "",
+	}
+	for key, value := range annotations {
+		g.AddAnnotation(key, value)
+	}
+
+	config := util.LifecycleConfig{
+		Config:  g,
+		Actions: util.LifecycleActionCreate | util.LifecycleActionDelete,
+		PreCreate: func(r *util.Runtime) error {
+			r.SetID(uuid.NewString())
+			return nil
+		},
+		PostCreate: func(r *util.Runtime) error {
+			state, err := r.State()
+			if err != nil {
+				return err
+			}
+			for key, value := range annotations {
+				actual, ok := state.Annotations[key]
+				t.Ok(ok && actual == value, fmt.Sprintf("annotation %q is in the container state", key))
+			}
+			// Runtimes may add annotations of their own under the reserved
+			// org.opencontainers namespace, so only report unknown ones.
+			for key := range state.Annotations {
+				if _, ok := annotations[key]; !ok && !strings.HasPrefix(key, "org.opencontainers.") {
+					_ = t.YAML(map[string]string{"unexpected annotation": key})
+				}
+			}
+			r.Kill("KILL")
+			return util.WaitingForStatus(*r, util.LifecycleStatusStopped, time.Second*10, time.Second)
+		},
+	}
+
+	err = util.RuntimeLifecycleValidate(config)
+	if err != nil {
+		diagnostic := map[string]string{
+			"error": err.Error(),
+		}
+		if e, ok := err.(*exec.ExitError); ok {
+			if len(e.Stderr) > 0 {
+				diagnostic["stderr"] = string(e.Stderr)
+			}
+		}
+		t.Fail("container lifecycle failed")
+		_ = t.YAML(diagnostic)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// checkBundle creates a container from the bundle at bundleDir, passing dir
+// to the runtime as the bundle path, and checks the bundle path in its
+// state. The paths are compared once resolved, since dir may be a symlink
+// and the runtime may report either path.
+func checkBundle(t *tap.T, bundleDir, dir string) error {
+	r, err := util.NewRuntime(util.RuntimeCommand, dir)
+	if err != nil {
+		return err
+	}
+	defer r.ForceDelete()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		return err
+	}
+	if err := g.SetProcessArgs([]string{"true"}); err != nil {
+		return err
+	}
+	if err := r.SetConfig(g); err != nil {
+		return err
+	}
+	r.SetID(uuid.NewString())
+	if err := r.Create(); err != nil {
+		return err
+	}
+	state, err := r.State()
+	if err != nil {
+		return err
+	}
+
+	expected, err := filepath.EvalSymlinks(bundleDir)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(state.Bundle) {
+		err = fmt.Errorf("state bundle %q is not an absolute path", state.Bundle)
+	} else if actual, e := filepath.EvalSymlinks(state.Bundle); e != nil {
+		err = fmt.Errorf("state bundle %q: %w", state.Bundle, e)
+	} else if actual != expected {
+		err = fmt.Errorf("state bundle %q resolves to %q, expected %q", state.Bundle, actual, expected)
+	}
+	util.SpecErrorOK(t, err == nil, specerror.NewError(specerror.StateBundleAbs, fmt.Errorf("`bundle` is the absolute path to the container's bundle directory, given as %q", dir), rspecs.Version), err)
+	return nil
+}
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+	defer os.RemoveAll(bundleDir)
+
+	link := bundleDir + "-link"
+	if err := os.Symlink(bundleDir, link); err != nil {
+		util.Fatal(err)
+	}
+	defer os.Remove(link)
+
+	for _, dir := range []string{bundleDir, link} {
+		if err := checkBundle(t, bundleDir, dir); err != nil {
+			util.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mndrix/tap-go"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/specerror"
+	"github.com/opencontainers/runtime-tools/validation/util"
+)
+
+// clockTicks is USER_HZ, the unit of the start time in /proc/<pid>/stat,
+// which is 100 on every architecture Linux supports.
+const clockTicks = 100
+
+// uptime returns the seconds since boot from /proc/uptime.
+func uptime() (float64, error) {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected /proc/uptime %q", data)
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// startTime returns the seconds since boot at which pid started, along with
+// its state, from /proc/<pid>/stat.
+func startTime(pid int) (float64, string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, "", err
+	}
+	// The command name may contain spaces, so fields are counted from
+	// the closing parenthesis: state is field 3 and starttime field 22.
+	i := bytes.LastIndexByte(data, ')')
+	if i < 0 {
+		return 0, "", fmt.Errorf("unexpected /proc/%d/stat %q", pid, data)
+	}
+	fields := strings.Fields(string(data[i+1:]))
+	if len(fields) < 20 {
+		return 0, "", fmt.Errorf("unexpected /proc/%d/stat %q", pid, data)
+	}
+	ticks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, "", err
+	}
+	return float64(ticks) / clockTicks, fields[0], nil
+}
+
+func main() {
+	t := tap.New()
+	t.Header(0)
+	defer t.AutoPlan()
+
+	bundleDir, err := util.PrepareBundle()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	r, err := util.NewRuntime(util.RuntimeCommand, bundleDir)
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		util.Fatal(err)
+	}
+	defer r.Clean()
+
+	g, err := util.GetDefaultGenerator()
+	if err != nil {
+		util.Fatal(err)
+	}
+	if err := g.SetProcessArgs([]string{"sleep", "30"}); err != nil {
+		util.Fatal(err)
+	}
+	if err := r.SetConfig(g); err != nil {
+		util.Fatal(err)
+	}
+
+	before, err := uptime()
+	if err != nil {
+		util.Fatal(err)
+	}
+	r.SetID(uuid.NewString())
+	if err := r.Create(); err != nil {
+		util.Fatal(err)
+	}
+	state, err := r.State()
+	if err != nil {
+		util.Fatal(err)
+	}
+
+	// A stale pid belongs to a process which is gone, or which started
+	// before create was called; the ticks are rounded down, so allow one.
+	started, status, err := startTime(state.Pid)
+	if err == nil && (status == "Z" || status == "X") {
+		err = fmt.Errorf("process %d is %s", state.Pid, status)
+	} else if err == nil && started < before-1.0/clockTicks {
+		err = fmt.Errorf("process %d started at %.2fs since boot, before create was called at %.2fs", state.Pid, started, before)
+	}
+	util.SpecErrorOK(t, state.Pid > 0 && err == nil, specerror.NewError(specerror.StatePidProcess, fmt.Errorf("`pid` is the ID of the container process, which was started by create"), rspecs.Version), err)
+}
@@ -19,6 +19,7 @@ type Runtime struct {
 	RuntimeCommand string
 	BundleDir      string
 	PidFile        string
+	ConsoleSocket  string // path of the AF_UNIX socket receiving the pseudoterminal master
 	ID             string
 	stdout         *os.File
 	stderr         *os.File
@@ -70,6 +71,9 @@ func (r *Runtime) Create() (err error) {
 	if r.PidFile != "" {
 		args = append(args, "--pid-file", r.PidFile)
 	}
+	if r.ConsoleSocket != "" {
+		args = append(args, "--console-socket", r.ConsoleSocket)
+	}
 	if r.BundleDir != "" {
 		args = append(args, "--bundle", r.BundleDir)
 	}
@@ -127,6 +131,60 @@ func (r *Runtime) Start() (err error) {
 	return execWithStderrFallbackToStdout(cmd)
 }
 
+// Exec runs process in the running container with `exec --process`. The
+// standard streams of process then replace the ones ReadStandardStreams
+// returns.
+func (r *Runtime) Exec(process *rspecs.Process) (err error) {
+	if process == nil {
+		return errors.New("cannot exec a nil process")
+	}
+	data, err := json.Marshal(process)
+	if err != nil {
+		return err
+	}
+	id := uuid.NewString()
+	processPath := filepath.Join(r.bundleDir(), fmt.Sprintf("process-%s.json", id))
+	if err := os.WriteFile(processPath, data, 0o600); err != nil {
+		return err
+	}
+	defer os.Remove(processPath)
+
+	args := []string{"exec", "--process", processPath}
+	if r.ID != "" {
+		args = append(args, r.ID)
+	}
+	cmd := exec.Command(r.RuntimeCommand, args...)
+
+	// Any process still writing to the previous files holds descriptors of
+	// its own, so they can be closed before they are replaced.
+	for _, f := range []*os.File{r.stdout, r.stderr} {
+		if f != nil {
+			f.Close()
+		}
+	}
+	r.stdout, err = os.OpenFile(filepath.Join(r.bundleDir(), fmt.Sprintf("stdout-%s", id)), os.O_CREATE|os.O_EXCL|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = r.stdout
+	r.stderr, err = os.OpenFile(filepath.Join(r.bundleDir(), fmt.Sprintf("stderr-%s", id)), os.O_CREATE|os.O_EXCL|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = r.stderr
+
+	err = cmd.Run()
+	if e, ok := err.(*exec.ExitError); ok {
+		stdout, stderr, _ := r.ReadStandardStreams()
+		if len(stderr) == 0 {
+			stderr = stdout
+		}
+		e.Stderr = stderr
+		return e
+	}
+	return err
+}
+
 // State a container information
 func (r *Runtime) State() (rspecs.State, error) {
 	var args []string
@@ -154,6 +212,40 @@ func (r *Runtime) State() (rspecs.State, error) {
 	return state, err
 }
 
+// exitStatusFields are state fields that may carry the exit status of a
+// stopped container. The runtime-spec state does not define one, and
+// runtimes such as runc report none, so callers have to allow for that.
+var exitStatusFields = []string{"exitStatus", "exit_status", "exitCode"}
+
+// ExitStatus returns the exit status of the container process from the
+// runtime state, where the runtime reports one beyond the spec fields, and
+// the name of the field it was found in. The field is empty if none is
+// reported.
+func (r *Runtime) ExitStatus() (status int, field string, err error) {
+	var args []string
+	args = append(args, "state")
+	if r.ID != "" {
+		args = append(args, r.ID)
+	}
+
+	// The state is read as raw JSON, since State drops the fields the spec
+	// does not define.
+	out, err := exec.Command(r.RuntimeCommand, args...).Output()
+	if err != nil {
+		return 0, "", err
+	}
+	var state map[string]interface{}
+	if err := json.Unmarshal(out, &state); err != nil {
+		return 0, "", err
+	}
+	for _, field := range exitStatusFields {
+		if status, ok := state[field].(float64); ok {
+			return int(status), field, nil
+		}
+	}
+	return 0, "", nil
+}
+
 // Kill a container
 func (r *Runtime) Kill(sig string) (err error) {
 	var args []string
@@ -104,10 +104,23 @@ func Skip(message string, diagnostic interface{}) {
 // SpecErrorOK generates TAP output indicating whether a spec code test passed or failed.
 func SpecErrorOK(t *tap.T, expected bool, specErr error, detailedErr error) {
 	t.Ok(expected, specErr.(*specerror.Error).Err.Err.Error())
-	diagnostic := map[string]string{
+	errorDiagnostic(t, map[string]string{
 		"reference": specErr.(*specerror.Error).Err.Reference,
+	}, detailedErr)
+}
+
+// ErrorOK generates TAP output indicating whether a test passed or failed,
+// for checks which no spec requirement covers.
+func ErrorOK(t *tap.T, expected bool, description string, detailedErr error) {
+	t.Ok(expected, description)
+	if detailedErr != nil {
+		errorDiagnostic(t, map[string]string{}, detailedErr)
 	}
+}
 
+// errorDiagnostic adds the details of detailedErr, if any, to diagnostic and
+// writes it out.
+func errorDiagnostic(t *tap.T, diagnostic map[string]string, detailedErr error) {
 	if detailedErr != nil {
 		diagnostic["error"] = detailedErr.Error()
 		if e, ok := detailedErr.(*exec.ExitError); ok {
@@ -145,7 +158,9 @@ func GetDefaultGenerator() (*generate.Generator, error) {
 		return nil, err
 	}
 	g.SetRootPath(".")
-	g.SetProcessArgs([]string{"/runtimetest", "--path=/"})
+	if err := g.SetProcessArgs([]string{"/runtimetest", "--path=/"}); err != nil {
+		return nil, err
+	}
 	return &g, err
 }
 